@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/cdclaxton/shortest-path-bfs/pkg/spbfs"
+)
+
+func main() {
+
+	// Command line arguments
+	configFilepath := flag.String("config", "config.json", "Location of the JSON config file")
+	serveAddr := flag.String("serve", "", "Address on which to answer queries over HTTP, e.g. :8080 (optional)")
+	resume := flag.Bool("resume", false, "Continue an interrupted run from its checkpoint, appending to the output file")
+	flag.Parse()
+
+	log.Println("Shortest path calculator using a bipartite to unipartite transformation and the")
+	log.Println("Breadth First Search and exhaustive search algorithms with reachable vertex optimisation step")
+
+	// Stop the run cleanly on an interrupt, keeping the results found so far
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Read the JSON configuration
+	log.Println("Reading configuration ...")
+	config, err := spbfs.ReadConfig(*configFilepath)
+	if err != nil {
+		log.Fatalf("[!] %v\n", err)
+	}
+
+	if *resume {
+		config.Output.Resume = true
+	}
+
+	// Load the graph once and answer queries until interrupted
+	if len(*serveAddr) > 0 {
+		if err := spbfs.Serve(ctx, config, *serveAddr); err != nil {
+			log.Fatalf("[!] %v\n", err)
+		}
+		return
+	}
+
+	if err := spbfs.PerformBfs(ctx, config); err != nil {
+		log.Fatalf("[!] %v\n", err)
+	}
+}
@@ -0,0 +1,8 @@
+module github.com/cdclaxton/shortest-path-bfs
+
+go 1.22
+
+require (
+	github.com/golang-collections/collections v0.0.0-20130729185459-604e922904d3
+	github.com/klauspost/compress v1.18.0
+)
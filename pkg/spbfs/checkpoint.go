@@ -0,0 +1,310 @@
+package spbfs
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// The checkpoint is synced to disk after this many units or this long, whichever comes first, so
+// that a normal run isn't slowed down by syncing after every unit
+const (
+	checkpointBatchSize = 64
+	checkpointInterval  = 5 * time.Second
+)
+
+// checkpointEntry records a work unit whose results have been written to the output file
+type checkpointEntry struct {
+	ID                    int    `json:"id"`                      // position of the unit amongst all of the units
+	Source                string `json:"source"`                  // source entity ID of the unit
+	SourceDataSource      string `json:"source_data_source"`      // data source of the source entity
+	DestinationDataSource string `json:"destination_data_source"` // data source of the destination entities
+	Destinations          string `json:"destinations"`            // fingerprint of the destination entity IDs
+	OutputFormat          string `json:"output_format"`           // format of the output file
+	PairsProcessed        int    `json:"pairs_processed"`         // number of entity pairs processed
+	PairsWithPaths        int    `json:"pairs_with_paths"`        // number of entity pairs connected by a path
+	PathsFound            int    `json:"paths_found"`             // number of paths found
+	PairsTimedOut         int    `json:"pairs_timed_out"`         // number of entity pairs whose search ran out of time
+	Offset                int64  `json:"offset"`                  // size of the output file once the results were written
+}
+
+// matches returns true if the entry is for the work unit written in the output format
+func (e *checkpointEntry) matches(unit workUnit, outputFormat string) bool {
+	return e.ID == unit.id && e.Source == unit.source &&
+		e.SourceDataSource == unit.sourceDataSource &&
+		e.DestinationDataSource == unit.destinationDataSource &&
+		e.Destinations == destinationsFingerprint(unit.destinations) &&
+		e.OutputFormat == outputFormat
+}
+
+// destinationsFingerprint returns a short identifier for the destination entity IDs of a unit,
+// so that a change to the destinations is detected without storing them in the checkpoint
+func destinationsFingerprint(destinations []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(destinations, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// checkpointFilePath returns the location of the checkpoint file for the output file
+func checkpointFilePath(outputFile string) string {
+	return outputFile + ".checkpoint"
+}
+
+// removeCheckpoint removes the checkpoint file (if it exists)
+func removeCheckpoint(filepath string) error {
+
+	if err := os.Remove(filepath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("unable to remove checkpoint file %v: %w", filepath, err)
+	}
+
+	return nil
+}
+
+// resumeState represents the work completed by earlier runs
+type resumeState struct {
+	completed map[int]checkpointEntry // completed units by ID
+	summary   Summary                 // counts for the completed units
+	offset    int64                   // size of the output file after the last completed unit
+	size      int64                   // size of the complete lines of the checkpoint file
+}
+
+// readCheckpoint reads the units completed by earlier runs from the checkpoint file. The last line
+// is ignored if it's incomplete, as the run may have stopped while writing it.
+func readCheckpoint(filepath string) (resumeState, error) {
+
+	state := resumeState{completed: map[int]checkpointEntry{}}
+
+	file, err := os.Open(filepath)
+	if errors.Is(err, os.ErrNotExist) {
+		log.Printf("Checkpoint file %v doesn't exist, so starting from the beginning\n", filepath)
+		return state, nil
+	}
+
+	if err != nil {
+		return resumeState{}, fmt.Errorf("unable to open checkpoint file %v: %w", filepath, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	var invalid error
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+
+		// Only the last line may be incomplete
+		if invalid != nil {
+			return resumeState{}, invalid
+		}
+
+		var entry checkpointEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			invalid = fmt.Errorf("%w: line %v of checkpoint file %v: %v", ErrInvalidRow, lineNumber, filepath, err)
+			continue
+		}
+
+		state.size += int64(len(scanner.Bytes())) + 1
+		state.completed[entry.ID] = entry
+		state.summary.PairsProcessed += entry.PairsProcessed
+		state.summary.PairsWithPaths += entry.PairsWithPaths
+		state.summary.PathsFound += entry.PathsFound
+		state.summary.PairsTimedOut += entry.PairsTimedOut
+
+		if entry.Offset > state.offset {
+			state.offset = entry.Offset
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return resumeState{}, fmt.Errorf("unable to read checkpoint file %v: %w", filepath, err)
+	}
+
+	return state, nil
+}
+
+// remaining returns the units that haven't been completed, numbered in the order that they're
+// processed. An error is returned if the completed units don't match the units or were written
+// in a different output format.
+func (s *resumeState) remaining(units []workUnit, outputFormat string) ([]workUnit, error) {
+
+	for id, entry := range s.completed {
+		if id < 0 || id >= len(units) || !entry.matches(units[id], outputFormat) {
+			return nil, fmt.Errorf("%w: the checkpoint doesn't match the pairs of entities to search or the output format (unit %v)", ErrConfig, id)
+		}
+	}
+
+	remaining := []workUnit{}
+	for _, unit := range units {
+		if _, done := s.completed[unit.id]; !done {
+			unit.index = len(remaining)
+			remaining = append(remaining, unit)
+		}
+	}
+
+	return remaining, nil
+}
+
+// numResults returns the number of results written for the completed units
+func (s *resumeState) numResults() int {
+	return s.summary.PathsFound + s.summary.PairsTimedOut
+}
+
+// openOutputFile opens the output file for writing. When resuming, the results of the completed
+// units are kept and anything written after them is removed.
+func openOutputFile(outputFile string, state resumeState) (*os.File, error) {
+
+	if state.offset == 0 {
+		file, err := os.Create(outputFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open output file %v for writing: %w", outputFile, err)
+		}
+		return file, nil
+	}
+
+	file, err := os.OpenFile(outputFile, os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open output file %v to resume: %w", outputFile, err)
+	}
+
+	info, err := file.Stat()
+	if err == nil && info.Size() < state.offset {
+		err = fmt.Errorf("%w: output file %v is shorter than recorded in the checkpoint", ErrConfig, outputFile)
+	}
+
+	if err == nil {
+		err = file.Truncate(state.offset)
+	}
+
+	if err == nil {
+		_, err = file.Seek(state.offset, io.SeekStart)
+	}
+
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("unable to resume output file %v: %w", outputFile, err)
+	}
+
+	return file, nil
+}
+
+// checkpoint records the completed units in a file next to the output file. The entries are
+// written in batches, each after the output file has been synced, so an entry is never on disk
+// before the results it records.
+type checkpoint struct {
+	file         *os.File          // checkpoint file
+	output       *os.File          // output file whose size is recorded
+	outputFormat string            // format of the output file
+	encoder      *json.Encoder     // encoder for the entries
+	pending      []checkpointEntry // entries waiting for the next sync
+	lastSync     time.Time         // time of the last sync
+}
+
+// newCheckpoint opens the checkpoint file for writing. When resuming, the units completed by
+// earlier runs are kept and an incomplete last line is removed.
+func newCheckpoint(filepath string, output *os.File, outputFormat string, state resumeState) (*checkpoint, error) {
+
+	var file *os.File
+	var err error
+
+	if state.size == 0 {
+		file, err = os.Create(filepath)
+	} else {
+		file, err = os.OpenFile(filepath, os.O_RDWR, 0644)
+		if err == nil {
+			err = file.Truncate(state.size)
+		}
+		if err == nil {
+			_, err = file.Seek(state.size, io.SeekStart)
+		}
+	}
+
+	if err != nil {
+		if file != nil {
+			file.Close()
+		}
+		return nil, fmt.Errorf("unable to open checkpoint file %v for writing: %w", filepath, err)
+	}
+
+	return &checkpoint{
+		file:         file,
+		output:       output,
+		outputFormat: outputFormat,
+		encoder:      json.NewEncoder(file),
+		lastSync:     time.Now(),
+	}, nil
+}
+
+// record adds a unit to the checkpoint once its results have been written to the output file.
+// The checkpoint is synced once enough units are waiting or enough time has passed.
+func (c *checkpoint) record(result unitResult) error {
+
+	offset, err := c.output.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	c.pending = append(c.pending, checkpointEntry{
+		ID:                    result.unit.id,
+		Source:                result.unit.source,
+		SourceDataSource:      result.unit.sourceDataSource,
+		DestinationDataSource: result.unit.destinationDataSource,
+		Destinations:          destinationsFingerprint(result.unit.destinations),
+		OutputFormat:          c.outputFormat,
+		PairsProcessed:        result.pairsProcessed,
+		PairsWithPaths:        result.pairsWithPaths,
+		PathsFound:            len(result.results) - result.pairsTimedOut,
+		PairsTimedOut:         result.pairsTimedOut,
+		Offset:                offset,
+	})
+
+	if len(c.pending) >= checkpointBatchSize || time.Since(c.lastSync) >= checkpointInterval {
+		return c.sync()
+	}
+
+	return nil
+}
+
+// sync syncs the output file, then writes and syncs the waiting entries
+func (c *checkpoint) sync() error {
+
+	c.lastSync = time.Now()
+
+	if len(c.pending) == 0 {
+		return nil
+	}
+
+	if err := c.output.Sync(); err != nil {
+		return err
+	}
+
+	for _, entry := range c.pending {
+		if err := c.encoder.Encode(entry); err != nil {
+			return fmt.Errorf("unable to write checkpoint file %v: %w", c.file.Name(), err)
+		}
+	}
+	c.pending = nil
+
+	return c.file.Sync()
+}
+
+// close writes the waiting entries and closes the checkpoint file. It does nothing if the
+// checkpoint has already been closed.
+func (c *checkpoint) close() error {
+
+	if c.file == nil {
+		return nil
+	}
+
+	err := c.sync()
+	if closeErr := c.file.Close(); err == nil {
+		err = closeErr
+	}
+	c.file = nil
+
+	return err
+}
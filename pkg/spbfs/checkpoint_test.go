@@ -0,0 +1,246 @@
+package spbfs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+// checkpointTestRun builds the graph for the full test data and returns the config, writing the
+// output to a temporary directory
+func checkpointTestRun(t *testing.T, format string) (*Graph, PathConfig) {
+
+	config, err := ReadConfig("./test/test-data-full/config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config.Output.OutputFile = filepath.Join(t.TempDir(), "results."+format)
+	config.Output.OutputFormat = format
+	config.Output.Resume = true
+
+	g, err := buildGraph(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return g, config
+}
+
+// readFile reads the whole of a file, failing the test on an error
+func readFile(t *testing.T, path string) []byte {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return content
+}
+
+// countdownContext is a context that is cancelled once its Err method has been called a number
+// of times, so that a run stops at the same point each time
+type countdownContext struct {
+	context.Context
+	remaining int64
+}
+
+// Err returns context.Canceled once the countdown has finished
+func (c *countdownContext) Err() error {
+	if atomic.AddInt64(&c.remaining, -1) < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+// interruptedRun runs the search until it's cancelled part way through, returning the lines of
+// the checkpoint it leaves behind
+func interruptedRun(t *testing.T, g *Graph, config PathConfig) [][]byte {
+	t.Helper()
+
+	// A single worker stops at the same unit each time
+	config.Output.Workers = 1
+
+	ctx := &countdownContext{Context: context.Background(), remaining: 100}
+	if _, err := performBfs(ctx, g, config); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the run to be cancelled, got %v\n", err)
+	}
+
+	lines := bytes.SplitAfter(readFile(t, checkpointFilePath(config.Output.OutputFile)), []byte("\n"))
+	if len(lines) < 4 {
+		t.Fatalf("Expected at least 3 units to be completed, got %q\n", lines)
+	}
+
+	return lines
+}
+
+func TestPerformBfsResume(t *testing.T) {
+
+	for _, format := range []string{OutputFormatCSV, OutputFormatJSONL, OutputFormatJSON} {
+
+		g, config := checkpointTestRun(t, format)
+
+		// Complete run, which doesn't leave a checkpoint behind
+		config.Output.Resume = false
+		expectedSummary, err := performBfs(context.Background(), g, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expectedOutput := readFile(t, config.Output.OutputFile)
+		checkpointFile := checkpointFilePath(config.Output.OutputFile)
+
+		if _, err := os.Stat(checkpointFile); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("%v: didn't expect a checkpoint file after a complete run, got %v\n", format, err)
+		}
+
+		// Simulate the run stopping part way through: the checkpoint has an incomplete last line
+		// and the output has part of the results of the next unit
+		lines := interruptedRun(t, g, config)
+
+		var entry checkpointEntry
+		if err := json.Unmarshal(lines[2], &entry); err != nil {
+			t.Fatal(err)
+		}
+
+		checkpoint := append(bytes.Join(lines[:3], nil), []byte(`{"id":3,"sou`)...)
+		if err := os.WriteFile(checkpointFile, checkpoint, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		output := append(append([]byte{}, expectedOutput[:entry.Offset]...), []byte("e-partial,set-1")...)
+		if err := os.WriteFile(config.Output.OutputFile, output, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		// Resume the run
+		config.Output.Resume = true
+		summary, err := performBfs(context.Background(), g, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(expectedSummary, summary) {
+			t.Errorf("%v: expected summary %v, got %v\n", format, expectedSummary, summary)
+		}
+
+		if actual := readFile(t, config.Output.OutputFile); !bytes.Equal(expectedOutput, actual) {
+			t.Errorf("%v: expected output:\n%s\ngot:\n%s\n", format, expectedOutput, actual)
+		}
+
+		// The checkpoint is removed once the run is complete
+		if _, err := os.Stat(checkpointFile); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("%v: didn't expect a checkpoint file after the resumed run, got %v\n", format, err)
+		}
+	}
+}
+
+func TestPerformBfsResumeWithoutCheckpoint(t *testing.T) {
+	g, config := checkpointTestRun(t, OutputFormatCSV)
+
+	summary, err := performBfs(context.Background(), g, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.PairsProcessed != 45 || summary.PathsFound != 14 {
+		t.Errorf("Expected a complete run, got %v\n", summary)
+	}
+
+	if !FilesHaveSameContent("./test/test-data-full/expected_results.csv", config.Output.OutputFile) {
+		t.Fatal("Actual results differ from expected results")
+	}
+}
+
+func TestPerformBfsResumeDifferentPairs(t *testing.T) {
+	g, config := checkpointTestRun(t, OutputFormatCSV)
+
+	interruptedRun(t, g, config)
+	expectedOutput := readFile(t, config.Output.OutputFile)
+
+	testCases := []struct {
+		description string
+		update      func(c *PathConfig)
+	}{
+		{"sources", func(c *PathConfig) {
+			c.Entities.DataSources[0].EntityIds = c.Entities.DataSources[0].EntityIds[1:]
+		}},
+		{"destinations", func(c *PathConfig) {
+			c.Entities.DataSources[1].EntityIds = c.Entities.DataSources[1].EntityIds[1:]
+		}},
+		{"output format", func(c *PathConfig) { c.Output.OutputFormat = OutputFormatJSONL }},
+	}
+
+	for _, testCase := range testCases {
+
+		// The checkpoint is for different pairs or a different format
+		changed := config
+		changed.Entities.DataSources = []DataSource{config.Entities.DataSources[0], config.Entities.DataSources[1]}
+		testCase.update(&changed)
+
+		if _, err := performBfs(context.Background(), g, changed); !errors.Is(err, ErrConfig) {
+			t.Errorf("%v: expected %v, got %v\n", testCase.description, ErrConfig, err)
+		}
+	}
+
+	// The results of the earlier run are kept
+	if actual := readFile(t, config.Output.OutputFile); !bytes.Equal(expectedOutput, actual) {
+		t.Fatal("Results of the earlier run were changed")
+	}
+}
+
+func TestPerformBfsWithoutResume(t *testing.T) {
+	g, config := checkpointTestRun(t, OutputFormatCSV)
+	config.Output.Resume = false
+
+	// The checkpoint is written even if the run isn't resumed
+	interruptedRun(t, g, config)
+
+	// A new run that isn't resumed removes the checkpoint of the earlier run, so it can't be
+	// resumed afterwards
+	checkpointFile := checkpointFilePath(config.Output.OutputFile)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := performBfs(ctx, g, config); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the run to be cancelled, got %v\n", err)
+	}
+
+	if content := readFile(t, checkpointFile); len(content) != 0 {
+		t.Errorf("Expected an empty checkpoint, got %s\n", content)
+	}
+
+	// The checkpoint is removed once the run is complete
+	if _, err := performBfs(context.Background(), g, config); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(checkpointFile); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Didn't expect a checkpoint file, got %v\n", err)
+	}
+
+	if !FilesHaveSameContent("./test/test-data-full/expected_results.csv", config.Output.OutputFile) {
+		t.Fatal("Actual results differ from expected results")
+	}
+}
+
+func TestReadCheckpointInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.csv.checkpoint")
+
+	// Only the last line may be incomplete
+	content := "{\"id\":0,\"offset\":10}\n{\"id\n{\"id\":1,\"offset\":20}\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := readCheckpoint(path); !errors.Is(err, ErrInvalidRow) {
+		t.Errorf("Expected %v, got %v\n", ErrInvalidRow, err)
+	}
+}
@@ -0,0 +1,1167 @@
+package spbfs
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang-collections/collections/queue"
+	"github.com/golang-collections/collections/set"
+)
+
+// CompactGraph is a read-only form of a Graph where the vertex identifiers are interned to integers
+// and the adjacency lists are stored in compressed sparse row (CSR) form. The vertices are numbered
+// in alphabetical order of their identifiers, so the adjacency lists are sorted in the same order
+// as Graph.AdjacentTo and the searches return the same paths as the Graph. The virtual document
+// vertices sort first, so they are numbered from 0 to numDocuments-1. The compact graph also holds
+// the weights and documents of the edges, so the Graph isn't needed once it has been frozen.
+type CompactGraph struct {
+	identifiers  []string            // identifier of each vertex
+	index        map[string]uint32   // vertex identifier to its integer
+	offsets      []uint32            // the neighbours of vertex i are neighbours[offsets[i]:offsets[i+1]]
+	neighbours   []uint32            // concatenated adjacency lists
+	weights      []float64           // cost of each edge in neighbours (nil if every edge costs 1)
+	documents    map[uint64][]string // sorted document IDs supporting each edge (see compactEdgeKey)
+	numDocuments uint32              // number of virtual document vertices
+	states       sync.Pool           // reusable search state
+}
+
+// searchState holds the state of a single search so that it can be reused without reallocating
+type searchState struct {
+	visited    []uint32  // generation in which each vertex was visited
+	parent     []uint32  // vertex from which each vertex was discovered
+	depth      []uint32  // number of hops from the root to each vertex
+	queue      []uint32  // queue of vertices to visit
+	cost       []float64 // lowest known cost from the root to each vertex (least cost searches only)
+	reached    []uint32  // generation in which each vertex was given a cost (least cost searches only)
+	generation uint32    // current search
+}
+
+// Freeze builds the compact, read-only form of the graph
+func (g *Graph) Freeze() *CompactGraph {
+
+	// Collect the identifiers of the vertices, including those without outgoing edges
+	vertices := set.New()
+	numEdges := 0
+
+	for source, destinations := range g.Nodes {
+		vertices.Insert(source)
+		destinations.Do(func(d interface{}) {
+			vertices.Insert(d)
+		})
+		numEdges += destinations.Len()
+	}
+
+	identifiers := ConvertSetToSlice(vertices)
+
+	index := make(map[string]uint32, len(identifiers))
+	for i, identifier := range identifiers {
+		index[identifier] = uint32(i)
+	}
+
+	// Build the adjacency lists, along with the weights (if any) and the documents of the edges
+	offsets := make([]uint32, len(identifiers)+1)
+	neighbours := make([]uint32, 0, numEdges)
+	documents := make(map[uint64][]string, len(g.Documents))
+
+	var weights []float64
+	if len(g.Weights) > 0 {
+		weights = make([]float64, 0, numEdges)
+	}
+
+	for i, identifier := range identifiers {
+		if destinations, ok := g.Nodes[identifier]; ok {
+			for _, d := range ConvertSetToSlice(destinations) {
+				neighbours = append(neighbours, index[d])
+
+				if weights != nil {
+					weights = append(weights, g.Weight(identifier, d))
+				}
+
+				if !g.HasDocuments() {
+					continue
+				}
+
+				key := compactEdgeKey(uint32(i), index[d])
+				if _, done := documents[key]; !done {
+					if supporting, ok := g.Documents[edgeKey(identifier, d)]; ok {
+						documents[key] = ConvertSetToSlice(supporting)
+					}
+				}
+			}
+		}
+		offsets[i+1] = uint32(len(neighbours))
+	}
+
+	// The identifiers of the document vertices start with a NUL character, so they sort first
+	numDocuments := 0
+	for numDocuments < len(identifiers) && isDocumentVertex(identifiers[numDocuments]) {
+		numDocuments++
+	}
+
+	c := &CompactGraph{
+		identifiers:  identifiers,
+		index:        index,
+		offsets:      offsets,
+		neighbours:   neighbours,
+		weights:      weights,
+		documents:    documents,
+		numDocuments: uint32(numDocuments),
+	}
+
+	c.states.New = func() interface{} {
+		return &searchState{
+			visited: make([]uint32, len(identifiers)),
+			parent:  make([]uint32, len(identifiers)),
+			depth:   make([]uint32, len(identifiers)),
+		}
+	}
+
+	return c
+}
+
+// NumVertices returns the number of vertices in the graph
+func (c *CompactGraph) NumVertices() int {
+	return len(c.identifiers)
+}
+
+// NumEdges returns the number of directed edges in the graph
+func (c *CompactGraph) NumEdges() int {
+	return len(c.neighbours)
+}
+
+// adjacent returns the neighbours of a vertex
+func (c *CompactGraph) adjacent(v uint32) []uint32 {
+	return c.neighbours[c.offsets[v]:c.offsets[v+1]]
+}
+
+// compactEdgeKey returns the key for an edge that is independent of its direction
+func compactEdgeKey(v uint32, w uint32) uint64 {
+	if v > w {
+		v, w = w, v
+	}
+	return uint64(v)<<32 | uint64(w)
+}
+
+// present returns the integer of a vertex if it has at least one edge (equivalent to it being in
+// Graph.Nodes)
+func (c *CompactGraph) present(identifier string) (uint32, bool) {
+	v, ok := c.index[identifier]
+	return v, ok && c.hasOutgoingEdges(v)
+}
+
+// edge returns the position in neighbours of the edge from v to w
+func (c *CompactGraph) edge(v uint32, w uint32) (int, bool) {
+
+	adjacent := c.adjacent(v)
+	i := sort.Search(len(adjacent), func(i int) bool {
+		return adjacent[i] >= w
+	})
+
+	if i == len(adjacent) || adjacent[i] != w {
+		return 0, false
+	}
+
+	return int(c.offsets[v]) + i, true
+}
+
+// edgeWeight returns the cost of the edge at a position in neighbours
+func (c *CompactGraph) edgeWeight(e int) float64 {
+	if c.weights == nil {
+		return 1.0
+	}
+	return c.weights[e]
+}
+
+// Weight returns the cost of the edge between source and destination vertices (default 1)
+func (c *CompactGraph) Weight(source string, destination string) float64 {
+
+	v, sourcePresent := c.index[source]
+	w, destinationPresent := c.index[destination]
+	if !sourcePresent || !destinationPresent {
+		return 1.0
+	}
+
+	e, ok := c.edge(v, w)
+	if !ok {
+		return 1.0
+	}
+
+	return c.edgeWeight(e)
+}
+
+// EdgeDocuments returns the sorted document IDs supporting the edge between source and destination vertices
+func (c *CompactGraph) EdgeDocuments(source string, destination string) []string {
+
+	v, sourcePresent := c.index[source]
+	w, destinationPresent := c.index[destination]
+	if !sourcePresent || !destinationPresent {
+		return []string{}
+	}
+
+	return append([]string{}, c.documents[compactEdgeKey(v, w)]...)
+}
+
+// hopDocuments returns the sorted document IDs supporting the hop between two entities, including
+// the documents whose virtual document vertex connects them (see Graph.hopDocuments)
+func (c *CompactGraph) hopDocuments(source string, destination string) []string {
+
+	documents := c.EdgeDocuments(source, destination)
+
+	v, sourcePresent := c.index[source]
+	u, destinationPresent := c.index[destination]
+	if !sourcePresent || !destinationPresent {
+		return documents
+	}
+
+	shared := false
+	for _, w := range c.adjacent(v) {
+
+		// The document vertices sort before the entities
+		if !c.isDocument(w) {
+			break
+		}
+
+		if _, ok := c.edge(w, u); ok {
+			documents = append(documents, strings.TrimPrefix(c.identifiers[w], documentVertexPrefix))
+			shared = true
+		}
+	}
+
+	if shared {
+		sort.Strings(documents)
+	}
+
+	return documents
+}
+
+// hopCost returns the cost of the hop between two entities (see Graph.hopCost)
+func (c *CompactGraph) hopCost(source string, destination string) float64 {
+
+	cost := math.Inf(1)
+
+	v, sourcePresent := c.index[source]
+	u, destinationPresent := c.index[destination]
+	if !sourcePresent || !destinationPresent {
+		return cost
+	}
+
+	if e, ok := c.edge(v, u); ok {
+		cost = c.edgeWeight(e)
+	}
+
+	for i, w := range c.adjacent(v) {
+
+		// The document vertices sort before the entities
+		if !c.isDocument(w) {
+			break
+		}
+
+		if e, ok := c.edge(w, u); ok {
+			cost = math.Min(cost, c.edgeWeight(int(c.offsets[v])+i)+c.edgeWeight(e))
+		}
+	}
+
+	return cost
+}
+
+// PathDocuments returns the document IDs supporting each hop of a path
+func (c *CompactGraph) PathDocuments(path []string) [][]string {
+
+	documents := [][]string{}
+
+	for i := 0; i < len(path)-1; i++ {
+		documents = append(documents, c.hopDocuments(path[i], path[i+1]))
+	}
+
+	return documents
+}
+
+// PathCost returns the total cost of the hops on a path
+func (c *CompactGraph) PathCost(path []string) float64 {
+
+	cost := 0.0
+
+	for i := 0; i < len(path)-1; i++ {
+		cost += c.hopCost(path[i], path[i+1])
+	}
+
+	return cost
+}
+
+// isDocument returns true if the vertex is a virtual document vertex
+func (c *CompactGraph) isDocument(v uint32) bool {
+	return v < c.numDocuments
+}
+
+// entityNeighbours returns the entities one hop from an entity, passing through the virtual
+// document vertices in the same way as Graph.entityNeighbours
+func (c *CompactGraph) entityNeighbours(v uint32) []uint32 {
+
+	adjacent := c.adjacent(v)
+	if len(adjacent) == 0 || !c.isDocument(adjacent[0]) {
+		return adjacent
+	}
+
+	seen := map[uint32]bool{v: true}
+	neighbours := []uint32{}
+
+	add := func(w uint32) {
+		if !seen[w] {
+			seen[w] = true
+			neighbours = append(neighbours, w)
+		}
+	}
+
+	for _, w := range adjacent {
+		if !c.isDocument(w) {
+			add(w)
+			continue
+		}
+
+		for _, u := range c.adjacent(w) {
+			add(u)
+		}
+	}
+
+	return neighbours
+}
+
+// hasOutgoingEdges returns true if the vertex has at least one edge (equivalent to it being in Graph.Nodes)
+func (c *CompactGraph) hasOutgoingEdges(v uint32) bool {
+	return c.offsets[v+1] > c.offsets[v]
+}
+
+// AdjacentTo returns the vertices adjacent to a given vertex (none if the vertex isn't in the graph)
+func (c *CompactGraph) AdjacentTo(source string) []string {
+
+	v, ok := c.index[source]
+	if !ok || !c.hasOutgoingEdges(v) {
+		return nil
+	}
+
+	adjacent := make([]string, 0, c.offsets[v+1]-c.offsets[v])
+	for _, w := range c.adjacent(v) {
+		adjacent = append(adjacent, c.identifiers[w])
+	}
+
+	return adjacent
+}
+
+// acquire gets a search state ready for a new search
+func (c *CompactGraph) acquire() *searchState {
+
+	s := c.states.Get().(*searchState)
+	s.queue = s.queue[:0]
+	s.generation++
+
+	// Reset the visited vertices if the generation has wrapped around
+	if s.generation == 0 {
+		for i := range s.visited {
+			s.visited[i] = 0
+		}
+		for i := range s.reached {
+			s.reached[i] = 0
+		}
+		s.generation = 1
+	}
+
+	return s
+}
+
+// release returns a search state so that it can be reused
+func (c *CompactGraph) release(s *searchState) {
+	c.states.Put(s)
+}
+
+// seen returns true if the vertex has been visited in the current search
+func (s *searchState) seen(v uint32) bool {
+	return s.visited[v] == s.generation
+}
+
+// visit marks a vertex as visited from its parent at a given depth and adds it to the queue
+func (s *searchState) visit(v uint32, parent uint32, depth uint32) {
+	s.visited[v] = s.generation
+	s.parent[v] = parent
+	s.depth[v] = depth
+	s.queue = append(s.queue, v)
+}
+
+// expand visits the unvisited entities one hop from a vertex. A virtual document vertex is marked
+// as visited without being queued, as all of its entities are visited the first time it's passed
+// through.
+func (c *CompactGraph) expand(s *searchState, v uint32, depth uint32) {
+
+	for _, w := range c.adjacent(v) {
+
+		if s.seen(w) {
+			continue
+		}
+
+		if !c.isDocument(w) {
+			s.visit(w, v, depth)
+			continue
+		}
+
+		s.visited[w] = s.generation
+		for _, u := range c.adjacent(w) {
+			if !s.seen(u) {
+				s.visit(u, v, depth)
+			}
+		}
+	}
+}
+
+// lineage builds the Vertex lineage from the root of the search to a vertex
+func (c *CompactGraph) lineage(s *searchState, v uint32) *Vertex {
+
+	// Walk back through the parents to the root
+	path := []uint32{v}
+	for s.depth[v] > 0 {
+		v = s.parent[v]
+		path = append(path, v)
+	}
+
+	// Rebuild the lineage from the root
+	var vertex *Vertex
+	for depth := 0; depth < len(path); depth++ {
+		next := makeVertex(c.identifiers[path[len(path)-1-depth]], depth)
+		next.Parent = vertex
+		vertex = &next
+	}
+
+	return vertex
+}
+
+// ReachableSet is the set of vertices found by CompactGraph.ReachableVertices. It refers to the
+// visited vertices of the search state rather than copying their identifiers, so Release must be
+// called once it's no longer needed.
+type ReachableSet struct {
+	c *CompactGraph
+	s *searchState
+}
+
+// Has returns true if the vertex is reachable
+func (r *ReachableSet) Has(identifier string) bool {
+	v, ok := r.c.index[identifier]
+	return ok && !r.c.isDocument(v) && r.s.seen(v)
+}
+
+// Len returns the number of reachable vertices, including the root
+func (r *ReachableSet) Len() int {
+	return len(r.s.queue)
+}
+
+// Identifiers returns the identifiers of the reachable vertices in alphabetical order
+func (r *ReachableSet) Identifiers() []string {
+
+	// The vertices are numbered in alphabetical order of their identifiers
+	vertices := append([]uint32{}, r.s.queue...)
+	sort.Slice(vertices, func(i, j int) bool {
+		return vertices[i] < vertices[j]
+	})
+
+	identifiers := make([]string, len(vertices))
+	for i, v := range vertices {
+		identifiers[i] = r.c.identifiers[v]
+	}
+
+	return identifiers
+}
+
+// Release returns the search state so that it can be reused. The set can't be used afterwards.
+func (r *ReachableSet) Release() {
+	if r.s != nil {
+		r.c.release(r.s)
+		r.s = nil
+	}
+}
+
+// ReachableVertices finds all vertices reachable within m steps. The set must be released once
+// it's no longer needed.
+func (c *CompactGraph) ReachableVertices(ctx context.Context, root string, maxDepth int) (bool, *ReachableSet, error) {
+
+	// Preconditions
+	if len(root) == 0 {
+		return false, nil, fmt.Errorf("%w: root", ErrEmptyVertex)
+	}
+
+	if maxDepth < 0 {
+		return false, nil, fmt.Errorf("%w: maximum depth %v", ErrInvalidDepth, maxDepth)
+	}
+
+	// Check that the root vertex exists
+	r, present := c.index[root]
+	if !present || !c.hasOutgoingEdges(r) {
+		return false, nil, nil
+	}
+
+	s := c.acquire()
+	s.visit(r, r, 0)
+
+	for head := 0; head < len(s.queue); head++ {
+
+		// Stop if the search has been cancelled or has run out of time
+		if err := ctx.Err(); err != nil {
+			c.release(s)
+			return false, nil, err
+		}
+
+		v := s.queue[head]
+
+		// Depth of any vertices adjacent to v
+		newDepth := s.depth[v] + 1
+		if newDepth > uint32(maxDepth) {
+			continue
+		}
+
+		c.expand(s, v, newDepth)
+	}
+
+	// The visited vertices are kept in the search state until the set is released
+	return true, &ReachableSet{c: c, s: s}, nil
+}
+
+// Neighbourhood finds the vertices between minDepth and maxDepth hops from the root, in order of
+// the number of hops, along with a shortest path to each. Only the vertices for which include
+// returns true are returned (all of them if include is nil), although the paths can pass through
+// the others. The number of vertices returned is limited to maxResults (0 means no limit).
+func (c *CompactGraph) Neighbourhood(ctx context.Context, root string, minDepth int, maxDepth int, maxResults int,
+	include func(identifier string) bool) (bool, [][]string, error) {
+
+	// Preconditions
+	if len(root) == 0 {
+		return false, nil, fmt.Errorf("%w: root", ErrEmptyVertex)
+	}
+
+	if maxDepth < 0 {
+		return false, nil, fmt.Errorf("%w: maximum depth %v", ErrInvalidDepth, maxDepth)
+	}
+
+	if minDepth < 0 || minDepth > maxDepth {
+		return false, nil, fmt.Errorf("%w: minimum depth %v", ErrInvalidDepth, minDepth)
+	}
+
+	if maxResults < 0 {
+		return false, nil, fmt.Errorf("%w: maximum number of results %v", ErrInvalidArgument, maxResults)
+	}
+
+	// Check that the root vertex exists
+	r, present := c.index[root]
+	if !present || !c.hasOutgoingEdges(r) {
+		return false, nil, nil
+	}
+
+	s := c.acquire()
+	defer c.release(s)
+
+	s.visit(r, r, 0)
+
+	// The vertices are discovered in order of depth, so the search can stop once there are enough
+	paths := [][]string{}
+
+	for head := 0; head < len(s.queue); head++ {
+
+		// Stop if the search has been cancelled or has run out of time
+		if err := ctx.Err(); err != nil {
+			return false, nil, err
+		}
+
+		v := s.queue[head]
+
+		if s.depth[v] >= uint32(minDepth) && v != r && (include == nil || include(c.identifiers[v])) {
+			paths = append(paths, c.lineage(s, v).flatten())
+
+			if maxResults > 0 && len(paths) == maxResults {
+				break
+			}
+		}
+
+		// Depth of any vertices adjacent to v
+		newDepth := s.depth[v] + 1
+		if newDepth > uint32(maxDepth) {
+			continue
+		}
+
+		c.expand(s, v, newDepth)
+	}
+
+	return true, paths, nil
+}
+
+// Bfs performs a Breadth First Search in the graph
+func (c *CompactGraph) Bfs(ctx context.Context, root string, goal string, maxDepth int) (bool, *Vertex, error) {
+
+	// Preconditions
+	if err := checkSearch(root, goal, maxDepth); err != nil {
+		return false, nil, err
+	}
+
+	// If the goal is the root, then return without traversing the graph
+	if root == goal {
+		v := makeVertex(root, 0)
+		return true, &v, nil
+	}
+
+	r, rootPresent := c.index[root]
+	g, goalPresent := c.index[goal]
+	if !rootPresent || !goalPresent {
+		return false, nil, nil
+	}
+
+	s := c.acquire()
+	defer c.release(s)
+
+	s.visit(r, r, 0)
+
+	for head := 0; head < len(s.queue); head++ {
+
+		// Stop if the search has been cancelled or has run out of time
+		if err := ctx.Err(); err != nil {
+			return false, nil, err
+		}
+
+		v := s.queue[head]
+
+		// If the vertex is the goal, then return
+		if v == g {
+			return true, c.lineage(s, v), nil
+		}
+
+		// Depth of any vertices adjacent to v
+		newDepth := s.depth[v] + 1
+		if newDepth > uint32(maxDepth) {
+			continue
+		}
+
+		c.expand(s, v, newDepth)
+	}
+
+	// The goal was not found
+	return false, nil, nil
+}
+
+// AllPaths finds all the paths from root to goal up to a maximum depth
+func (c *CompactGraph) AllPaths(ctx context.Context, root string, goal string, maxDepth int) ([]*TreeNode, error) {
+
+	// Preconditions
+	if err := checkSearch(root, goal, maxDepth); err != nil {
+		return nil, err
+	}
+
+	// If the goal is the root, then return without traversing the graph
+	treeNode := makeTreeNode(root, root == goal)
+	if treeNode.marked {
+		return []*TreeNode{treeNode}, nil
+	}
+
+	// List of complete nodes (where goal has been found)
+	complete := []*TreeNode{}
+
+	if _, present := c.index[root]; !present {
+		return complete, nil
+	}
+
+	// Nodes to 'spider' from
+	qCurrent := queue.New()
+	qCurrent.Enqueue(treeNode)
+
+	// Nodes to 'spider' from on the next iteration
+	qNext := queue.New()
+
+	for numSteps := 0; numSteps < maxDepth; numSteps++ {
+
+		for qCurrent.Len() > 0 {
+
+			// Stop if the search has been cancelled or has run out of time
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			// Take a tree node from the queue representing a vertex
+			node := qCurrent.Dequeue().(*TreeNode)
+
+			// Walk through each of the adjacent vertices
+			for _, w := range c.entityNeighbours(c.index[node.name]) {
+
+				adjIdentifier := c.identifiers[w]
+
+				if !node.containsVertex(adjIdentifier) {
+
+					marked := adjIdentifier == goal
+					child, err := node.makeChild(adjIdentifier, marked)
+					if err != nil {
+						return nil, err
+					}
+
+					if marked {
+						complete = append(complete, child)
+					} else {
+						qNext.Enqueue(child)
+					}
+				}
+			}
+		}
+
+		qCurrent = qNext
+		qNext = queue.New()
+	}
+
+	return complete, nil
+}
+
+// costEntry is an entry in the priority queue of a least cost search
+type costEntry struct {
+	v      uint32  // vertex
+	parent uint32  // vertex from which it was reached
+	depth  uint32  // number of hops from the root
+	cost   float64 // cost from the root
+}
+
+// costHeap is a priority queue ordered by cost, then depth, then vertex (and so identifier)
+type costHeap []costEntry
+
+func (h costHeap) Len() int { return len(h) }
+
+func (h costHeap) Less(i, j int) bool {
+	if h[i].cost != h[j].cost {
+		return h[i].cost < h[j].cost
+	}
+	if h[i].depth != h[j].depth {
+		return h[i].depth < h[j].depth
+	}
+	return h[i].v < h[j].v
+}
+
+func (h costHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *costHeap) Push(x interface{}) { *h = append(*h, x.(costEntry)) }
+
+func (h *costHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	*h = old[:n-1]
+	return e
+}
+
+// leastCostSearch runs Dijkstra's algorithm from the root, settling vertices up to the maximum
+// cost. Each settled vertex is visited in the search state. The search stops when the goal is
+// settled (if there is a goal).
+func (c *CompactGraph) leastCostSearch(ctx context.Context, s *searchState, r uint32, goal uint32, hasGoal bool,
+	maxCost float64) error {
+
+	// The costs are only needed by least cost searches
+	if s.cost == nil {
+		s.cost = make([]float64, len(c.identifiers))
+		s.reached = make([]uint32, len(c.identifiers))
+	}
+
+	h := &costHeap{}
+
+	// relax records a cost to a vertex if it's the lowest found so far
+	relax := func(from costEntry, w uint32, cost float64) {
+
+		if s.seen(w) || cost > maxCost {
+			return
+		}
+
+		// Only keep the first path found with the lowest cost
+		if s.reached[w] == s.generation && s.cost[w] <= cost {
+			return
+		}
+
+		s.reached[w] = s.generation
+		s.cost[w] = cost
+		heap.Push(h, costEntry{v: w, parent: from.v, depth: from.depth + 1, cost: cost})
+	}
+
+	s.reached[r] = s.generation
+	s.cost[r] = 0
+	heap.Push(h, costEntry{v: r, parent: r})
+
+	for h.Len() > 0 {
+
+		// Stop if the search has been cancelled or has run out of time
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Take the vertex with the lowest cost
+		e := heap.Pop(h).(costEntry)
+
+		// Ignore stale entries in the priority queue
+		if s.seen(e.v) {
+			continue
+		}
+
+		s.visit(e.v, e.parent, e.depth)
+
+		if hasGoal && e.v == goal {
+			break
+		}
+
+		// Walk through each of the adjacent entities, passing through the document vertices
+		for i, w := range c.adjacent(e.v) {
+
+			cost := e.cost + c.edgeWeight(int(c.offsets[e.v])+i)
+
+			if !c.isDocument(w) {
+				relax(e, w, cost)
+				continue
+			}
+
+			for j, u := range c.adjacent(w) {
+				if u != e.v {
+					relax(e, u, cost+c.edgeWeight(int(c.offsets[w])+j))
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Dijkstra finds the least cost path from root to goal with a cost up to maxCost
+func (c *CompactGraph) Dijkstra(ctx context.Context, root string, goal string, maxCost float64) (bool, *Vertex, error) {
+
+	// Preconditions
+	if len(root) == 0 {
+		return false, nil, fmt.Errorf("%w: root", ErrEmptyVertex)
+	}
+
+	if len(goal) == 0 {
+		return false, nil, fmt.Errorf("%w: goal", ErrEmptyVertex)
+	}
+
+	if maxCost < 0 {
+		return false, nil, fmt.Errorf("%w: maximum cost %v", ErrInvalidCost, maxCost)
+	}
+
+	// Check that the root and goal vertices exist
+	r, rootPresent := c.present(root)
+	g, goalPresent := c.index[goal]
+	if !rootPresent || !goalPresent {
+		return false, nil, nil
+	}
+
+	s := c.acquire()
+	defer c.release(s)
+
+	if err := c.leastCostSearch(ctx, s, r, g, true, maxCost); err != nil {
+		return false, nil, err
+	}
+
+	if !s.seen(g) {
+		return false, nil, nil
+	}
+
+	// Record the cost of each vertex on the path
+	vertex := c.lineage(s, g)
+	for p := vertex; p != nil; p = p.Parent {
+		p.Cost = s.cost[c.index[p.Identifier]]
+	}
+
+	return true, vertex, nil
+}
+
+// ReachableWithinCost finds all vertices reachable from the root with a cost up to maxCost. The
+// set must be released once it's no longer needed.
+func (c *CompactGraph) ReachableWithinCost(ctx context.Context, root string, maxCost float64) (bool, *ReachableSet, error) {
+
+	// Preconditions
+	if len(root) == 0 {
+		return false, nil, fmt.Errorf("%w: root", ErrEmptyVertex)
+	}
+
+	if maxCost < 0 {
+		return false, nil, fmt.Errorf("%w: maximum cost %v", ErrInvalidCost, maxCost)
+	}
+
+	// Check that the root vertex exists
+	r, present := c.present(root)
+	if !present {
+		return false, nil, nil
+	}
+
+	s := c.acquire()
+
+	if err := c.leastCostSearch(ctx, s, r, 0, false, maxCost); err != nil {
+		c.release(s)
+		return false, nil, err
+	}
+
+	// The settled vertices are kept in the search state until the set is released
+	return true, &ReachableSet{c: c, s: s}, nil
+}
+
+// expandFrontier expands a BFS frontier, which is the vertices in the queue of the search state
+// from start onwards, by one level. It returns the start of the next frontier and the meeting
+// vertex (if any), which is the newly discovered vertex that minimises the depth in the other search.
+func (c *CompactGraph) expandFrontier(s *searchState, start int, other *searchState) (int, uint32, bool) {
+
+	end := len(s.queue)
+	for _, v := range s.queue[start:end] {
+		c.expand(s, v, s.depth[v]+1)
+	}
+
+	var meeting uint32
+	met := false
+
+	for _, w := range s.queue[end:] {
+		if other.seen(w) && (!met || other.depth[w] < other.depth[meeting]) {
+			meeting = w
+			met = true
+		}
+	}
+
+	return end, meeting, met
+}
+
+// BidirectionalBfs performs a Breadth First Search from both the root and the goal, meeting in the middle.
+// It assumes the graph is undirected and returns a path with the same number of hops as Bfs.
+func (c *CompactGraph) BidirectionalBfs(ctx context.Context, root string, goal string, maxDepth int) (bool, *Vertex, error) {
+
+	// Preconditions
+	if err := checkSearch(root, goal, maxDepth); err != nil {
+		return false, nil, err
+	}
+
+	// If the goal is the root, then return without traversing the graph
+	if root == goal {
+		v := makeVertex(root, 0)
+		return true, &v, nil
+	}
+
+	// Check that both vertices exist
+	r, rootPresent := c.present(root)
+	g, goalPresent := c.present(goal)
+	if !rootPresent || !goalPresent {
+		return false, nil, nil
+	}
+
+	// Vertices discovered from the root (forward) and from the goal (backward)
+	forward := c.acquire()
+	defer c.release(forward)
+
+	backward := c.acquire()
+	defer c.release(backward)
+
+	forward.visit(r, r, 0)
+	backward.visit(g, g, 0)
+
+	// Start of each frontier in the queue of its search
+	forwardStart, backwardStart := 0, 0
+
+	// Total number of hops covered by both searches
+	depth := 0
+
+	for depth < maxDepth && forwardStart < len(forward.queue) && backwardStart < len(backward.queue) {
+
+		// Stop if the search has been cancelled or has run out of time
+		if err := ctx.Err(); err != nil {
+			return false, nil, err
+		}
+
+		var meeting uint32
+		var met bool
+
+		// Expand the smaller frontier
+		if len(forward.queue)-forwardStart <= len(backward.queue)-backwardStart {
+			forwardStart, meeting, met = c.expandFrontier(forward, forwardStart, backward)
+		} else {
+			backwardStart, meeting, met = c.expandFrontier(backward, backwardStart, forward)
+		}
+
+		if met {
+			return true, joinLineages(c.lineage(forward, meeting), c.lineage(backward, meeting)), nil
+		}
+
+		depth++
+	}
+
+	// The goal was not found
+	return false, nil, nil
+}
+
+// AllShortestPaths finds every path from root to goal with the minimum number of hops, up to a
+// maximum depth. The paths are found from the predecessors of each vertex in the BFS DAG.
+func (c *CompactGraph) AllShortestPaths(ctx context.Context, root string, goal string, maxDepth int) ([][]string, error) {
+
+	// Preconditions
+	if err := checkSearch(root, goal, maxDepth); err != nil {
+		return nil, err
+	}
+
+	// If the goal is the root, then return without traversing the graph
+	if root == goal {
+		return [][]string{{root}}, nil
+	}
+
+	// Check that the root and goal vertices exist
+	r, rootPresent := c.present(root)
+	g, goalPresent := c.index[goal]
+	if !rootPresent || !goalPresent {
+		return [][]string{}, nil
+	}
+
+	s := c.acquire()
+	defer c.release(s)
+
+	s.visit(r, r, 0)
+
+	// The vertices preceding each vertex on a shortest path and the number of shortest paths from
+	// the root to it
+	predecessors := map[uint32][]uint32{}
+	numPaths := map[uint32]int{r: 1}
+
+	current := []uint32{r}
+
+	// Walk through the graph level by level until the goal is discovered
+	for d := uint32(0); d < uint32(maxDepth) && len(current) > 0; d++ {
+
+		// Stop if the search has been cancelled or has run out of time
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		next := []uint32{}
+
+		for _, v := range current {
+			for _, w := range c.entityNeighbours(v) {
+
+				if !s.seen(w) {
+					s.visit(w, v, d+1)
+					next = append(next, w)
+				} else if s.depth[w] != d+1 {
+					// The vertex was discovered on an earlier level, so this isn't a shortest path
+					continue
+				}
+
+				predecessors[w] = append(predecessors[w], v)
+				numPaths[w] += numPaths[v]
+			}
+		}
+
+		// All of the shortest paths to the goal have been found
+		if s.seen(g) {
+			break
+		}
+
+		current = next
+	}
+
+	// The goal was not found
+	if !s.seen(g) {
+		return [][]string{}, nil
+	}
+
+	// Walk backwards from the goal through the predecessors to build each path
+	paths := make([][]string, 0, numPaths[g])
+
+	var walk func(v uint32, suffix []string)
+	walk = func(v uint32, suffix []string) {
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		path := append([]string{c.identifiers[v]}, suffix...)
+
+		if v == r {
+			paths = append(paths, path)
+			return
+		}
+
+		for _, p := range predecessors[v] {
+			walk(p, path)
+		}
+	}
+
+	walk(g, []string{})
+
+	// The walk may have been too long
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Sort the paths so that the order is deterministic
+	sort.Slice(paths, func(i, j int) bool {
+		return lessPath(paths[i], paths[j])
+	})
+
+	return paths, nil
+}
+
+// bfsAvoiding performs a Breadth First Search that doesn't use the removed vertices or directed edges
+func (c *CompactGraph) bfsAvoiding(ctx context.Context, root string, goal string, maxDepth int,
+	removedVertices *set.Set, removedEdges *set.Set) ([]string, error) {
+
+	r, rootPresent := c.index[root]
+	g, goalPresent := c.index[goal]
+	if !rootPresent || !goalPresent {
+		return nil, nil
+	}
+
+	s := c.acquire()
+	defer c.release(s)
+
+	s.visit(r, r, 0)
+
+	for head := 0; head < len(s.queue); head++ {
+
+		// Stop if the search has been cancelled or has run out of time
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		v := s.queue[head]
+
+		if v == g {
+			return c.lineage(s, v).flatten(), nil
+		}
+
+		newDepth := s.depth[v] + 1
+		if newDepth > uint32(maxDepth) {
+			continue
+		}
+
+		for _, w := range c.entityNeighbours(v) {
+
+			if s.seen(w) || removedVertices.Has(c.identifiers[w]) ||
+				removedEdges.Has(c.identifiers[v]+"\x00"+c.identifiers[w]) {
+				continue
+			}
+
+			s.visit(w, v, newDepth)
+		}
+	}
+
+	return nil, nil
+}
+
+// KShortestPaths finds up to k loopless paths from root to goal with the fewest hops, up to a
+// maximum depth, using Yen's algorithm. The paths are returned in order of the number of hops.
+func (c *CompactGraph) KShortestPaths(ctx context.Context, root string, goal string, k int, maxDepth int) ([][]string, error) {
+
+	// Preconditions
+	if k < 1 {
+		return nil, fmt.Errorf("%w: number of paths %v", ErrInvalidArgument, k)
+	}
+
+	// Shortest path
+	found, vertex, err := c.Bfs(ctx, root, goal, maxDepth)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return [][]string{}, nil
+	}
+
+	return yen(ctx, vertex.flatten(), goal, k, maxDepth, c.bfsAvoiding)
+}
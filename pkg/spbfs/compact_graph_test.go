@@ -0,0 +1,508 @@
+package spbfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestCompactGraphEmpty(t *testing.T) {
+	g := NewGraph()
+	c := g.Freeze()
+
+	if c.NumVertices() != 0 || c.NumEdges() != 0 {
+		t.Fatalf("Expected empty graph, got %v vertices and %v edges\n", c.NumVertices(), c.NumEdges())
+	}
+
+	found, _, err := c.Bfs(context.Background(), "a", "b", 3)
+	if err != nil || found {
+		t.Fatal("Path found in an empty graph")
+	}
+}
+
+func TestCompactGraphAdjacentTo(t *testing.T) {
+	g := NewGraph()
+	g.AddDirected("b", "d")
+	g.AddDirected("b", "a")
+	g.AddDirected("b", "c")
+
+	c := g.Freeze()
+
+	if c.NumVertices() != 4 || c.NumEdges() != 3 {
+		t.Fatalf("Expected 4 vertices and 3 edges, got %v and %v\n", c.NumVertices(), c.NumEdges())
+	}
+
+	if !reflect.DeepEqual(c.AdjacentTo("b"), []string{"a", "c", "d"}) {
+		t.Fatalf("Unexpected adjacent vertices: %v\n", c.AdjacentTo("b"))
+	}
+
+	// A vertex with only incoming edges has no adjacent vertices
+	if c.AdjacentTo("a") != nil {
+		t.Fatalf("Expected no adjacent vertices, got %v\n", c.AdjacentTo("a"))
+	}
+}
+
+func TestCompactGraphReachableSet(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("c", "b")
+	g.AddUndirected("b", "a")
+	g.AddUndirected("d", "e")
+
+	c := g.Freeze()
+
+	found, reachable, err := c.ReachableVertices(context.Background(), "c", 2)
+	if err != nil || !found {
+		t.Fatalf("Expected c to be found, got %v (%v)\n", found, err)
+	}
+
+	if !reflect.DeepEqual([]string{"a", "b", "c"}, reachable.Identifiers()) || reachable.Len() != 3 {
+		t.Errorf("Unexpected reachable vertices %v\n", reachable.Identifiers())
+	}
+
+	if !reachable.Has("a") || reachable.Has("d") || reachable.Has("unknown") {
+		t.Error("Unexpected reachability of a, d or an unknown vertex")
+	}
+
+	// The search state is reused by the next search once the set is released
+	reachable.Release()
+	reachable.Release()
+
+	found, reachable, err = c.ReachableVertices(context.Background(), "d", 2)
+	if err != nil || !found {
+		t.Fatalf("Expected d to be found, got %v (%v)\n", found, err)
+	}
+	defer reachable.Release()
+
+	if !reflect.DeepEqual([]string{"d", "e"}, reachable.Identifiers()) || reachable.Has("a") {
+		t.Errorf("Unexpected reachable vertices %v\n", reachable.Identifiers())
+	}
+}
+
+func TestCompactGraphDirected(t *testing.T) {
+	g := NewGraph()
+	g.AddDirected("a", "b")
+	g.AddDirected("b", "c")
+
+	c := g.Freeze()
+
+	found, vertex, err := c.Bfs(context.Background(), "a", "c", 2)
+	if err != nil || !found || !reflect.DeepEqual(vertex.flatten(), []string{"a", "b", "c"}) {
+		t.Fatalf("Expected path a, b, c, got %v (%v)\n", found, err)
+	}
+
+	found, _, err = c.Bfs(context.Background(), "c", "a", 2)
+	if err != nil || found {
+		t.Fatal("Path found against the direction of the edges")
+	}
+
+	// The root must have outgoing edges, as for Graph.ReachableVertices
+	found, _, err = c.ReachableVertices(context.Background(), "c", 2)
+	if err != nil || found {
+		t.Fatal("Vertex c should not be found")
+	}
+}
+
+// TestCompactGraphSameAsGraph checks that the compact graph gives the same results as the graph
+func TestCompactGraphSameAsGraph(t *testing.T) {
+
+	graphs := []*Graph{
+		readTestGraph(t, []string{
+			"./test/test-data-full/entity_doc_1.csv",
+			"./test/test-data-full/entity_doc_2.csv",
+			"./test/test-data-full/entity_doc_3.csv",
+		}),
+		readTestGraph(t, []string{
+			"./test/test-data-full-2/entity_doc_1.csv",
+			"./test/test-data-full-2/entity_doc_2.csv",
+			"./test/test-data-full-2/entity_doc_3.csv",
+		}),
+		readTestGraph(t, []string{
+			"./test/test-data-full-3/entity_doc_1.csv",
+			"./test/test-data-full-3/entity_doc_2.csv",
+		}),
+	}
+
+	// A graph where a large document is a star
+	connections := largeDocumentConnections()
+	star, err := BipartiteToUnipartite(&connections, 3, LargeDocumentStar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	graphs = append(graphs, star)
+
+	// Weighted graphs, where the least cost paths differ from the shortest paths
+	weighted := readTestGraph(t, []string{
+		"./test/test-data-full/entity_doc_1.csv",
+		"./test/test-data-full/entity_doc_2.csv",
+		"./test/test-data-full/entity_doc_3.csv",
+	})
+	if err := ComputeEdgeWeights(weighted, nil, WeightCount, nil); err != nil {
+		t.Fatal(err)
+	}
+	graphs = append(graphs, weighted)
+
+	weightedStar, err := BipartiteToUnipartite(&connections, 3, LargeDocumentStar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ComputeEdgeWeights(weightedStar, nil, WeightCount, nil); err != nil {
+		t.Fatal(err)
+	}
+	weightedStar.SetWeight("e-1", documentVertex("d-1"), 0.25)
+	weightedStar.SetWeight(documentVertex("d-1"), "e-1", 0.25)
+	graphs = append(graphs, weightedStar)
+
+	for _, g := range graphs {
+		c := g.Freeze()
+
+		// The virtual document vertices aren't searched from or for
+		vertices := []string{}
+		for _, vertex := range g.listOfKeys() {
+			if !isDocumentVertex(vertex) {
+				vertices = append(vertices, vertex)
+			}
+		}
+
+		for _, root := range vertices {
+
+			if !reflect.DeepEqual(g.AdjacentTo(root), c.AdjacentTo(root)) {
+				t.Fatalf("%v: adjacent vertices differ\n", root)
+			}
+
+			for maxDepth := 0; maxDepth <= 4; maxDepth++ {
+
+				found1, reachable1, err1 := g.ReachableVertices(context.Background(), root, maxDepth)
+				found2, reachable2, err2 := c.ReachableVertices(context.Background(), root, maxDepth)
+
+				if err1 != nil || err2 != nil {
+					t.Fatalf("%v (max depth %v): unexpected errors %v and %v\n", root, maxDepth, err1, err2)
+				}
+
+				if found1 != found2 || !reflect.DeepEqual(ConvertSetToSlice(reachable1), reachable2.Identifiers()) ||
+					reachable1.Len() != reachable2.Len() {
+					t.Fatalf("%v (max depth %v): reachable vertices differ\n", root, maxDepth)
+				}
+
+				for _, vertex := range vertices {
+					if reachable1.Has(vertex) != reachable2.Has(vertex) {
+						t.Fatalf("%v (max depth %v): reachability of %v differs\n", root, maxDepth, vertex)
+					}
+				}
+				reachable2.Release()
+
+				maxCost := float64(maxDepth) / 2
+				found1, reachable1, err1 = g.ReachableWithinCost(context.Background(), root, maxCost)
+				found2, reachable2, err2 = c.ReachableWithinCost(context.Background(), root, maxCost)
+
+				if err1 != nil || err2 != nil {
+					t.Fatalf("%v (max cost %v): unexpected errors %v and %v\n", root, maxCost, err1, err2)
+				}
+
+				if found1 != found2 || !reflect.DeepEqual(ConvertSetToSlice(reachable1), reachable2.Identifiers()) {
+					t.Fatalf("%v (max cost %v): reachable vertices differ\n", root, maxCost)
+				}
+				reachable2.Release()
+
+				for _, goal := range vertices {
+
+					found1, vertex1, err1 := g.Bfs(context.Background(), root, goal, maxDepth)
+					found2, vertex2, err2 := c.Bfs(context.Background(), root, goal, maxDepth)
+
+					if err1 != nil || err2 != nil {
+						t.Fatalf("%v -> %v (max depth %v): unexpected errors %v and %v\n", root, goal, maxDepth, err1, err2)
+					}
+
+					if found1 != found2 {
+						t.Fatalf("%v -> %v (max depth %v): graph found %v, compact graph found %v\n",
+							root, goal, maxDepth, found1, found2)
+					}
+
+					if found1 && !reflect.DeepEqual(vertex1.flatten(), vertex2.flatten()) {
+						t.Fatalf("%v -> %v (max depth %v): paths differ: %v and %v\n",
+							root, goal, maxDepth, vertex1.flatten(), vertex2.flatten())
+					}
+
+					nodes1, err1 := g.AllPaths(context.Background(), root, goal, maxDepth)
+					nodes2, err2 := c.AllPaths(context.Background(), root, goal, maxDepth)
+
+					if err1 != nil || err2 != nil {
+						t.Fatalf("%v -> %v (max depth %v): unexpected errors %v and %v\n", root, goal, maxDepth, err1, err2)
+					}
+
+					paths1 := flattenAll(nodes1)
+					paths2 := flattenAll(nodes2)
+
+					if !reflect.DeepEqual(paths1, paths2) {
+						t.Fatalf("%v -> %v (max depth %v): all paths differ: %v and %v\n",
+							root, goal, maxDepth, paths1, paths2)
+					}
+
+					found1, vertex1, err1 = g.BidirectionalBfs(context.Background(), root, goal, maxDepth)
+					found2, vertex2, err2 = c.BidirectionalBfs(context.Background(), root, goal, maxDepth)
+
+					if err1 != nil || err2 != nil {
+						t.Fatalf("%v -> %v (max depth %v): unexpected errors %v and %v\n", root, goal, maxDepth, err1, err2)
+					}
+
+					if found1 != found2 || (found1 && !reflect.DeepEqual(vertex1.flatten(), vertex2.flatten())) {
+						t.Fatalf("%v -> %v (max depth %v): bidirectional paths differ\n", root, goal, maxDepth)
+					}
+
+					found1, vertex1, err1 = g.Dijkstra(context.Background(), root, goal, maxCost)
+					found2, vertex2, err2 = c.Dijkstra(context.Background(), root, goal, maxCost)
+
+					if err1 != nil || err2 != nil {
+						t.Fatalf("%v -> %v (max cost %v): unexpected errors %v and %v\n", root, goal, maxCost, err1, err2)
+					}
+
+					if found1 != found2 || (found1 && (!reflect.DeepEqual(vertex1.flatten(), vertex2.flatten()) ||
+						vertex1.Cost != vertex2.Cost)) {
+						t.Fatalf("%v -> %v (max cost %v): least cost paths differ\n", root, goal, maxCost)
+					}
+
+					if found1 && (g.PathCost(vertex1.flatten()) != c.PathCost(vertex2.flatten()) ||
+						!reflect.DeepEqual(g.PathDocuments(vertex1.flatten()), c.PathDocuments(vertex2.flatten()))) {
+						t.Fatalf("%v -> %v: path costs or documents differ\n", root, goal)
+					}
+
+					paths1, err1 = g.AllShortestPaths(context.Background(), root, goal, maxDepth)
+					paths2, err2 = c.AllShortestPaths(context.Background(), root, goal, maxDepth)
+
+					if err1 != nil || err2 != nil {
+						t.Fatalf("%v -> %v (max depth %v): unexpected errors %v and %v\n", root, goal, maxDepth, err1, err2)
+					}
+
+					if !reflect.DeepEqual(paths1, paths2) {
+						t.Fatalf("%v -> %v (max depth %v): all shortest paths differ: %v and %v\n",
+							root, goal, maxDepth, paths1, paths2)
+					}
+
+					paths1, err1 = g.KShortestPaths(context.Background(), root, goal, 3, maxDepth)
+					paths2, err2 = c.KShortestPaths(context.Background(), root, goal, 3, maxDepth)
+
+					if err1 != nil || err2 != nil {
+						t.Fatalf("%v -> %v (max depth %v): unexpected errors %v and %v\n", root, goal, maxDepth, err1, err2)
+					}
+
+					if !reflect.DeepEqual(paths1, paths2) {
+						t.Fatalf("%v -> %v (max depth %v): k shortest paths differ: %v and %v\n",
+							root, goal, maxDepth, paths1, paths2)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestCompactGraphNeighbourhood(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("a", "c")
+	g.AddUndirected("b", "d")
+	g.AddUndirected("d", "e")
+
+	c := g.Freeze()
+
+	testCases := []struct {
+		minDepth   int
+		maxDepth   int
+		maxResults int
+		expected   [][]string
+	}{
+		{0, 0, 0, [][]string{}},
+		{0, 2, 0, [][]string{{"a", "b"}, {"a", "c"}, {"a", "b", "d"}}},
+		{2, 3, 0, [][]string{{"a", "b", "d"}, {"a", "b", "d", "e"}}},
+		{1, 3, 2, [][]string{{"a", "b"}, {"a", "c"}}},
+	}
+
+	for _, testCase := range testCases {
+		found, paths, err := c.Neighbourhood(context.Background(), "a", testCase.minDepth, testCase.maxDepth, testCase.maxResults, nil)
+
+		if err != nil || !found {
+			t.Fatalf("Root vertex not found (%v)\n", err)
+		}
+
+		if !reflect.DeepEqual(testCase.expected, paths) {
+			t.Fatalf("Depth %v to %v (max %v results): expected %v, got %v\n",
+				testCase.minDepth, testCase.maxDepth, testCase.maxResults, testCase.expected, paths)
+		}
+	}
+
+	// The excluded vertices don't count towards the maximum number of results
+	found, paths, err := c.Neighbourhood(context.Background(), "a", 1, 3, 2, func(identifier string) bool {
+		return identifier != "b"
+	})
+
+	expected := [][]string{{"a", "c"}, {"a", "b", "d"}}
+	if err != nil || !found || !reflect.DeepEqual(expected, paths) {
+		t.Fatalf("Expected %v, got %v (%v)\n", expected, paths, err)
+	}
+
+	found, _, err = c.Neighbourhood(context.Background(), "z", 0, 2, 0, nil)
+	if err != nil || found {
+		t.Fatal("Vertex z should not be found")
+	}
+
+	// Invalid arguments
+	invalid := []struct {
+		root       string
+		minDepth   int
+		maxDepth   int
+		maxResults int
+		expected   error
+	}{
+		{"", 0, 2, 0, ErrEmptyVertex},
+		{"a", 0, -1, 0, ErrInvalidDepth},
+		{"a", 3, 2, 0, ErrInvalidDepth},
+		{"a", 0, 2, -1, ErrInvalidArgument},
+	}
+
+	for _, testCase := range invalid {
+		_, _, err := c.Neighbourhood(context.Background(), testCase.root, testCase.minDepth, testCase.maxDepth, testCase.maxResults, nil)
+		if !errors.Is(err, testCase.expected) {
+			t.Errorf("Expected %v, got %v\n", testCase.expected, err)
+		}
+	}
+}
+
+func TestCompactGraphConcurrentSearches(t *testing.T) {
+	g := readTestGraph(t, []string{
+		"./test/test-data-full/entity_doc_1.csv",
+		"./test/test-data-full/entity_doc_2.csv",
+		"./test/test-data-full/entity_doc_3.csv",
+	})
+	c := g.Freeze()
+	vertices := g.listOfKeys()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, root := range vertices {
+				for _, goal := range vertices {
+					found1, vertex1, err1 := g.Bfs(context.Background(), root, goal, 3)
+					found2, vertex2, err2 := c.Bfs(context.Background(), root, goal, 3)
+					if err1 != nil || err2 != nil || found1 != found2 || (found1 && !reflect.DeepEqual(vertex1.flatten(), vertex2.flatten())) {
+						t.Errorf("%v -> %v: results differ\n", root, goal)
+						return
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Benchmarks on a random graph with a million (directed) edges
+
+const (
+	benchmarkVertices = 100000
+	benchmarkEdges    = 500000 // undirected
+	benchmarkMaxDepth = 3
+)
+
+var (
+	benchmarkOnce    sync.Once
+	benchmarkGraph   *Graph
+	benchmarkCompact *CompactGraph
+)
+
+// buildBenchmarkGraph generates a random undirected graph
+func buildBenchmarkGraph() *Graph {
+	r := rand.New(rand.NewSource(1))
+	g := NewGraph()
+
+	for i := 0; i < benchmarkEdges; i++ {
+		a := r.Intn(benchmarkVertices)
+		b := r.Intn(benchmarkVertices)
+		if a != b {
+			g.AddUndirected(fmt.Sprintf("v-%v", a), fmt.Sprintf("v-%v", b))
+		}
+	}
+
+	return &g
+}
+
+func benchmarkGraphs() (*Graph, *CompactGraph) {
+	benchmarkOnce.Do(func() {
+		benchmarkGraph = buildBenchmarkGraph()
+		benchmarkCompact = benchmarkGraph.Freeze()
+	})
+	return benchmarkGraph, benchmarkCompact
+}
+
+// heapInUse returns the number of bytes on the heap after a garbage collection
+func heapInUse() uint64 {
+	var m runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&m)
+	return m.HeapAlloc
+}
+
+func BenchmarkGraphMemory(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		before := heapInUse()
+		g := buildBenchmarkGraph()
+		b.ReportMetric(float64(heapInUse()-before)/(1<<20), "MB")
+		runtime.KeepAlive(g)
+	}
+}
+
+func BenchmarkCompactGraphMemory(b *testing.B) {
+	g, _ := benchmarkGraphs()
+	for n := 0; n < b.N; n++ {
+		before := heapInUse()
+		c := g.Freeze()
+		b.ReportMetric(float64(heapInUse()-before)/(1<<20), "MB")
+		runtime.KeepAlive(c)
+	}
+}
+
+func BenchmarkGraphBfs(b *testing.B) {
+	g, _ := benchmarkGraphs()
+	r := rand.New(rand.NewSource(2))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		g.Bfs(context.Background(), fmt.Sprintf("v-%v", r.Intn(benchmarkVertices)), fmt.Sprintf("v-%v", r.Intn(benchmarkVertices)), benchmarkMaxDepth)
+	}
+}
+
+func BenchmarkCompactGraphBfs(b *testing.B) {
+	_, c := benchmarkGraphs()
+	r := rand.New(rand.NewSource(2))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		c.Bfs(context.Background(), fmt.Sprintf("v-%v", r.Intn(benchmarkVertices)), fmt.Sprintf("v-%v", r.Intn(benchmarkVertices)), benchmarkMaxDepth)
+	}
+}
+
+func BenchmarkGraphReachableVertices(b *testing.B) {
+	g, _ := benchmarkGraphs()
+	r := rand.New(rand.NewSource(3))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		g.ReachableVertices(context.Background(), fmt.Sprintf("v-%v", r.Intn(benchmarkVertices)), benchmarkMaxDepth)
+	}
+}
+
+func BenchmarkCompactGraphReachableVertices(b *testing.B) {
+	_, c := benchmarkGraphs()
+	r := rand.New(rand.NewSource(3))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_, reachable, _ := c.ReachableVertices(context.Background(), fmt.Sprintf("v-%v", r.Intn(benchmarkVertices)), benchmarkMaxDepth)
+		if reachable != nil {
+			reachable.Release()
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package spbfs
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// delimiterRune returns the delimiter for a CSV file, which must be a single character
+func delimiterRune(delimiter string) (rune, error) {
+
+	// Precondition
+	if utf8.RuneCountInString(delimiter) != 1 {
+		return 0, fmt.Errorf("%w: must be a single character: %q", ErrInvalidDelimiter, delimiter)
+	}
+
+	r, _ := utf8.DecodeRuneInString(delimiter)
+
+	if r == '"' || r == '\r' || r == '\n' || r == utf8.RuneError {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidDelimiter, delimiter)
+	}
+
+	return r, nil
+}
+
+// NewCSVWriter returns a CSV writer that quotes fields as required, using the delimiter
+func NewCSVWriter(w io.Writer, delimiter string) (*csv.Writer, error) {
+
+	comma, err := delimiterRune(delimiter)
+	if err != nil {
+		return nil, err
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+
+	return writer, nil
+}
+
+// formatCSVRecord returns a record as a single line of a CSV file (without the line ending)
+func formatCSVRecord(record []string, delimiter string) (string, error) {
+
+	var buffer bytes.Buffer
+
+	writer, err := NewCSVWriter(&buffer, delimiter)
+	if err != nil {
+		return "", err
+	}
+
+	if err := writer.Write(record); err != nil {
+		return "", fmt.Errorf("unable to format CSV record: %w", err)
+	}
+	writer.Flush()
+
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
@@ -0,0 +1,31 @@
+package spbfs
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestNewCSVWriterDelimiter(t *testing.T) {
+	var buffer bytes.Buffer
+
+	writer, err := NewCSVWriter(&buffer, "|")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writer.Write([]string{"a", "b|c"})
+	writer.Flush()
+
+	if expected := "a|\"b|c\"\n"; buffer.String() != expected {
+		t.Fatalf("Expected %q, got %q\n", expected, buffer.String())
+	}
+}
+
+func TestNewCSVWriterInvalidDelimiter(t *testing.T) {
+	for _, delimiter := range []string{"", ";;", "\"", "\r", "\n", "\xff"} {
+		if _, err := NewCSVWriter(&bytes.Buffer{}, delimiter); !errors.Is(err, ErrInvalidDelimiter) {
+			t.Errorf("%q: expected %v, got %v\n", delimiter, ErrInvalidDelimiter, err)
+		}
+	}
+}
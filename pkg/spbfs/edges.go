@@ -0,0 +1,134 @@
+package spbfs
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/golang-collections/collections/set"
+)
+
+// Edge represents an entity-entity relationship read from an edge list
+type Edge struct {
+	Source      string   // entity ID of the source vertex
+	Destination string   // entity ID of the destination vertex
+	Documents   []string // document IDs supporting the edge (if any)
+}
+
+// ReadEdgeList reads the edges from an edge list using its layout, skipping the edges of the
+// required entities. Each row has the source and destination entity IDs and, optionally, the
+// document IDs separated by semi-colons, as written for the unipartite graph.
+func ReadEdgeList(file InputFile, skipEntities *set.Set) ([]Edge, error) {
+
+	log.Printf("Reading edge list from: %v\n", file.Path)
+
+	if file.inputType() != InputTypeEdges {
+		return nil, fmt.Errorf("%w: input file %v isn't an edge list", ErrInvalidArgument, file.Path)
+	}
+
+	// Open the file and skip the header (if any)
+	r, header, closer, err := openInputCSV(file)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure the file is closed
+	defer closer.Close()
+
+	if header != nil && len(header) != 2 && len(header) != 3 {
+		return nil, fmt.Errorf("%w: expected 2 or 3 columns in the header of %v, got %v", ErrInvalidHeader, file.Path, header)
+	}
+
+	var edges []Edge
+
+	for {
+
+		// Read a row from the file
+		row, err := r.Read()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: error reading CSV file %v: %v", ErrInvalidRow, file.Path, err)
+		}
+
+		line, _ := r.FieldPos(0)
+
+		if len(row) != 2 && len(row) != 3 {
+			return nil, fmt.Errorf("%w: expected 2 or 3 fields on line %v of %v, got %v", ErrInvalidRow, line, file.Path, row)
+		}
+
+		edge := Edge{
+			Source:      row[0],
+			Destination: row[1],
+		}
+
+		if len(edge.Source) == 0 || len(edge.Destination) == 0 {
+			return nil, fmt.Errorf("%w: empty entity ID on line %v of %v", ErrInvalidRow, line, file.Path)
+		}
+
+		if edge.Source == edge.Destination {
+			return nil, fmt.Errorf("%w: %w: %v on line %v of %v", ErrInvalidRow, ErrSelfLoop, edge.Source, line, file.Path)
+		}
+
+		for _, entityID := range []string{edge.Source, edge.Destination} {
+			if isDocumentVertex(entityID) {
+				return nil, fmt.Errorf("%w: invalid entity ID %q on line %v of %v", ErrInvalidRow, entityID, line, file.Path)
+			}
+		}
+
+		if len(row) == 3 && len(row[2]) > 0 {
+			edge.Documents = strings.Split(row[2], documentDelimiter)
+		}
+
+		if !skipEntities.Has(edge.Source) && !skipEntities.Has(edge.Destination) {
+			edges = append(edges, edge)
+		}
+	}
+
+	log.Printf("Read %v edges from file %v\n", len(edges), file.Path)
+
+	return edges, nil
+}
+
+// AddEdgeLists adds the edges read from the edge lists to the graph, in one direction only if
+// the file's edges are directed. The document weights are updated with the weight of the file of
+// each document (the maximum if several).
+func AddEdgeLists(g *Graph, files []InputFile, skipEntities *set.Set, documentWeights map[string]float64) error {
+
+	for _, file := range files {
+
+		edges, err := ReadEdgeList(file, skipEntities)
+		if err != nil {
+			return err
+		}
+
+		for _, edge := range edges {
+
+			if file.Directed {
+				err = g.AddDirected(edge.Source, edge.Destination)
+			} else {
+				err = g.AddUndirected(edge.Source, edge.Destination)
+			}
+
+			if err != nil {
+				return fmt.Errorf("%w: edge list %v: %v", ErrInvalidRow, file.Path, err)
+			}
+
+			for _, documentID := range edge.Documents {
+				if err := g.AddDocument(edge.Source, edge.Destination, documentID); err != nil {
+					return fmt.Errorf("%w: edge list %v: %v", ErrInvalidRow, file.Path, err)
+				}
+
+				if weight, ok := documentWeights[documentID]; !ok || file.Weight > weight {
+					documentWeights[documentID] = file.Weight
+				}
+			}
+		}
+	}
+
+	return nil
+}
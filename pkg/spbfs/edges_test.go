@@ -0,0 +1,145 @@
+package spbfs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/golang-collections/collections/set"
+)
+
+// writeEdgeList writes the contents of an edge list to a temporary file
+func writeEdgeList(t *testing.T, contents string) string {
+
+	path := filepath.Join(t.TempDir(), "edges.csv")
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestReadEdgeList(t *testing.T) {
+	path := writeEdgeList(t, "e-1,e-2\ne-2,e-3,d-1;d-2\ne-4,e-1,\n")
+
+	edges, err := ReadEdgeList(InputFile{Path: path, Weight: 1.0, InputType: InputTypeEdges}, set.New("e-4"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Edge{
+		{Source: "e-1", Destination: "e-2"},
+		{Source: "e-2", Destination: "e-3", Documents: []string{"d-1", "d-2"}},
+	}
+
+	if !reflect.DeepEqual(expected, edges) {
+		t.Errorf("Expected %v, got %v\n", expected, edges)
+	}
+}
+
+func TestReadEdgeListInvalid(t *testing.T) {
+	hasHeader := true
+
+	testCases := []struct {
+		description string
+		contents    string
+		file        InputFile
+		expected    error
+	}{
+		{"self loop", "e-1,e-1\n", InputFile{InputType: InputTypeEdges}, ErrInvalidRow},
+		{"empty entity", "e-1,\n", InputFile{InputType: InputTypeEdges}, ErrInvalidRow},
+		{"document vertex", "e-1,\x00doc:d-1\n", InputFile{InputType: InputTypeEdges}, ErrInvalidRow},
+		{"too many fields", "e-1,e-2,d-1,d-2\n", InputFile{InputType: InputTypeEdges}, ErrInvalidRow},
+		{"header", "a,b,c,d\ne-1,e-2\n", InputFile{InputType: InputTypeEdges, HasHeader: &hasHeader}, ErrInvalidHeader},
+		{"entity-document file", "entity_id,document_id\n", InputFile{}, ErrInvalidArgument},
+	}
+
+	for _, testCase := range testCases {
+		testCase.file.Path = writeEdgeList(t, testCase.contents)
+
+		if _, err := ReadEdgeList(testCase.file, set.New()); !errors.Is(err, testCase.expected) {
+			t.Errorf("%v: expected %v, got %v\n", testCase.description, testCase.expected, err)
+		}
+	}
+
+	// A self loop is an invalid row
+	path := writeEdgeList(t, "e-1,e-2\ne-3,e-3\n")
+	if _, err := ReadEdgeList(InputFile{Path: path, InputType: InputTypeEdges}, set.New()); !errors.Is(err, ErrSelfLoop) {
+		t.Errorf("Expected %v, got %v\n", ErrSelfLoop, err)
+	}
+
+	// An edge list can't be read as an entity-document file
+	path = writeEdgeList(t, "e-1,e-2\n")
+	if _, err := ReadInputFile(InputFile{Path: path, InputType: InputTypeEdges}, set.New()); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("Expected %v, got %v\n", ErrInvalidArgument, err)
+	}
+}
+
+func TestBuildGraphDirectedEdges(t *testing.T) {
+	path := writeEdgeList(t, "e-2,e-1,d-1;d-2\ne-2,e-3\n")
+
+	config := PathConfig{
+		InputFiles: []InputFile{{Path: path, Weight: 1.0, InputType: InputTypeEdges, Directed: true}},
+		Output:     OutputConfig{EdgeWeight: WeightCount},
+	}
+
+	g, err := buildGraph(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !g.Nodes["e-2"].Has("e-1") || g.Nodes["e-1"] != nil {
+		t.Errorf("Expected a single directed edge from e-2 to e-1, got %v\n", g.Nodes)
+	}
+
+	if weight := g.Weight("e-2", "e-1"); weight != 0.5 {
+		t.Errorf("Expected the directed edge to have a weight of 0.5, got %v\n", weight)
+	}
+}
+
+func TestPerformBfsFromEdgeList(t *testing.T) {
+	config, err := ReadConfig("./test/test-data-full/config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := buildGraph(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Save the unipartite graph and use it as the input
+	unipartiteFile := filepath.Join(t.TempDir(), "unipartite.csv")
+	if err := g.WriteUndirectedEdgeList(unipartiteFile, config.Output.PathDelimiter); err != nil {
+		t.Fatal(err)
+	}
+
+	config.InputFiles = []InputFile{{
+		Path:      unipartiteFile,
+		Weight:    1.0,
+		Delimiter: config.Output.PathDelimiter,
+		InputType: InputTypeEdges,
+	}}
+	config.Output.OutputFile = filepath.Join(t.TempDir(), "results.csv")
+
+	if err := config.validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := buildGraph(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameGraph(t, g, reloaded)
+
+	if _, err := performBfs(context.Background(), reloaded, config); err != nil {
+		t.Fatal(err)
+	}
+
+	if !FilesHaveSameContent("./test/test-data-full/expected_results.csv", config.Output.OutputFile) {
+		t.Fatal("Actual results differ from expected results")
+	}
+}
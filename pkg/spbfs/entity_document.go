@@ -0,0 +1,382 @@
+package spbfs
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/golang-collections/collections/set"
+)
+
+// EntityDocument represents an entity-document relationship
+type EntityDocument struct {
+	EntityID   string // entity ID
+	DocumentID string // document ID
+}
+
+// ReadEntityDocumentGraphFromFile reads entity-document relationships from a comma-delimited file
+// with a header and the entity and document IDs in the first two columns, skipping the required
+// entities. The file may be compressed using gzip or zstd and "-" reads from standard input.
+func ReadEntityDocumentGraphFromFile(filepath string, skipEntities *set.Set) ([]EntityDocument, error) {
+	return ReadInputFile(InputFile{Path: filepath, Weight: 1.0}, skipEntities)
+}
+
+// inputColumns locates the entity and document IDs in the rows of an input file
+type inputColumns struct {
+	entity    int // index of the entity ID column
+	document  int // index of the document ID column
+	numFields int // number of fields in each row
+}
+
+// findInputColumns finds the entity and document columns from the header of the file (if any)
+func findInputColumns(file InputFile, header []string) (inputColumns, error) {
+
+	// Without column names the entity and document IDs are the only two columns
+	if !file.namedColumns() {
+		if header != nil && len(header) != 2 {
+			return inputColumns{}, fmt.Errorf("%w: expected 2 columns in the header of %v, got %v",
+				ErrInvalidHeader, file.Path, header)
+		}
+		return inputColumns{entity: 0, document: 1, numFields: 2}, nil
+	}
+
+	columns := inputColumns{entity: -1, document: -1, numFields: len(header)}
+
+	for i, name := range header {
+
+		// Spreadsheet exports may start with a byte order mark
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+
+		switch strings.TrimSpace(name) {
+		case file.EntityColumn:
+			columns.entity = i
+		case file.DocumentColumn:
+			columns.document = i
+		}
+	}
+
+	if columns.entity < 0 || columns.document < 0 {
+		return inputColumns{}, fmt.Errorf("%w: expected columns %q and %q in the header of %v, got %v",
+			ErrInvalidHeader, file.EntityColumn, file.DocumentColumn, file.Path, header)
+	}
+
+	return columns, nil
+}
+
+// openInputCSV opens an input file as CSV using its layout and reads the header (if any). The
+// header is nil if the file is empty. The number of fields in each row is checked by the caller.
+func openInputCSV(file InputFile) (*csv.Reader, []string, io.Closer, error) {
+
+	if err := file.validate(); err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: input file %v: %v", ErrInvalidArgument, file.Path, err)
+	}
+
+	// Open the file (or standard input) for reading, decompressing it if required
+	f, err := openInput(file.Path)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("couldn't open CSV file %v: %w", file.Path, err)
+	}
+
+	r := csv.NewReader(f)
+	r.Comma, _ = delimiterRune(file.delimiter())
+	r.FieldsPerRecord = -1
+
+	if len(file.Comment) > 0 {
+		r.Comment, _ = delimiterRune(file.Comment)
+	}
+
+	if !file.hasHeader() {
+		return r, nil, f, nil
+	}
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return r, nil, f, nil
+	}
+
+	if err != nil {
+		f.Close()
+		return nil, nil, nil, fmt.Errorf("%w: error reading the header of CSV file %v: %v", ErrInvalidHeader, file.Path, err)
+	}
+
+	return r, header, f, nil
+}
+
+// ReadInputFile reads entity-document relationships from a file using its layout, skipping the
+// required entities. A path of "-" reads from standard input and gzip or zstd compressed data is
+// decompressed.
+func ReadInputFile(file InputFile, skipEntities *set.Set) ([]EntityDocument, error) {
+
+	log.Printf("Reading entity-document data from: %v\n", file.Path)
+
+	if file.inputType() != InputTypeEntityDocument {
+		return nil, fmt.Errorf("%w: input file %v isn't an entity-document file", ErrInvalidArgument, file.Path)
+	}
+
+	// Open the file and read the column names from the header (if any)
+	r, header, closer, err := openInputCSV(file)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure the file is closed
+	defer closer.Close()
+
+	// Initialise the slice of connections
+	var connections []EntityDocument
+
+	// The file was empty
+	if file.hasHeader() && header == nil {
+		log.Printf("Read 0 rows from file %v\n", file.Path)
+		return connections, nil
+	}
+
+	columns, err := findInputColumns(file, header)
+	if err != nil {
+		return nil, err
+	}
+
+	numRowsRead := 0
+
+	for {
+
+		// Read a row from the file
+		row, err := r.Read()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: error reading CSV file %v: %v", ErrInvalidRow, file.Path, err)
+		}
+
+		numRowsRead++
+		line, _ := r.FieldPos(0)
+
+		if len(row) != columns.numFields {
+			return nil, fmt.Errorf("%w: expected %v fields on line %v of %v, got %v", ErrInvalidRow, columns.numFields, line, file.Path, row)
+		}
+
+		docEnt := EntityDocument{
+			EntityID:   row[columns.entity],
+			DocumentID: row[columns.document],
+		}
+
+		if len(docEnt.EntityID) == 0 || len(docEnt.DocumentID) == 0 {
+			return nil, fmt.Errorf("%w: empty entity or document ID on line %v of %v", ErrInvalidRow, line, file.Path)
+		}
+
+		if isDocumentVertex(docEnt.EntityID) {
+			return nil, fmt.Errorf("%w: invalid entity ID %q on line %v of %v", ErrInvalidRow, docEnt.EntityID, line, file.Path)
+		}
+
+		if !skipEntities.Has(docEnt.EntityID) {
+			connections = append(connections, docEnt)
+		}
+
+	}
+
+	log.Printf("Read %v rows from file %v\n", numRowsRead, file.Path)
+
+	return connections, nil
+}
+
+// ReadEntityDocumentGraph reads the entity-document graph from a list of files
+func ReadEntityDocumentGraph(files []string, skipEntities *set.Set) (*[]EntityDocument, error) {
+
+	var allConnections []EntityDocument
+
+	// Read the connections from each file
+	for _, filePath := range files {
+		conns, err := ReadEntityDocumentGraphFromFile(filePath, skipEntities)
+		if err != nil {
+			return nil, err
+		}
+		allConnections = append(allConnections, conns...)
+	}
+
+	return &allConnections, nil
+}
+
+// ReadInputFiles reads the entity-document graph from a list of input files and returns the
+// weight of each document, taken from the file it was read from (the maximum if several)
+func ReadInputFiles(files []InputFile, skipEntities *set.Set) (*[]EntityDocument, map[string]float64, error) {
+
+	var allConnections []EntityDocument
+	documentWeights := make(map[string]float64)
+
+	// Read the connections from each file
+	for _, file := range files {
+		conns, err := ReadInputFile(file, skipEntities)
+		if err != nil {
+			return nil, nil, err
+		}
+		allConnections = append(allConnections, conns...)
+
+		for _, conn := range conns {
+			if weight, ok := documentWeights[conn.DocumentID]; !ok || file.Weight > weight {
+				documentWeights[conn.DocumentID] = file.Weight
+			}
+		}
+	}
+
+	return &allConnections, documentWeights, nil
+}
+
+// Policies for documents that connect more than the maximum number of entities
+const (
+	LargeDocumentSkip   = "skip"   // ignore the document
+	LargeDocumentStar   = "star"   // connect the entities via a virtual document vertex
+	LargeDocumentClique = "clique" // connect every pair of entities
+)
+
+// documentVertexPrefix is prepended to a document ID to make a virtual document vertex. It starts
+// with a NUL character, which isn't allowed at the start of an entity ID, so that a virtual vertex
+// can't be mistaken for an entity.
+const documentVertexPrefix = "\x00doc:"
+
+// documentVertex returns the identifier of the virtual vertex representing a document
+func documentVertex(documentID string) string {
+	return documentVertexPrefix + documentID
+}
+
+// isDocumentVertex returns true if the vertex is a virtual vertex representing a document
+func isDocumentVertex(vertex string) bool {
+	return strings.HasPrefix(vertex, documentVertexPrefix)
+}
+
+// validLargeDocumentPolicy returns true if the policy for large documents is recognised
+func validLargeDocumentPolicy(policy string) bool {
+	return policy == LargeDocumentSkip || policy == LargeDocumentStar || policy == LargeDocumentClique
+}
+
+// addClique connects every pair of entities in a document
+func addClique(g *Graph, documentID string, entities []string) error {
+	for i := 0; i < len(entities)-1; i++ {
+		for j := i + 1; j < len(entities); j++ {
+			if err := g.AddUndirected(entities[i], entities[j]); err != nil {
+				return err
+			}
+			if err := g.AddDocument(entities[i], entities[j], documentID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addStar connects each of the entities to a virtual vertex representing the document
+func addStar(g *Graph, documentID string, entities []string) error {
+	centre := documentVertex(documentID)
+	for _, entity := range entities {
+		if err := g.AddUndirected(centre, entity); err != nil {
+			return err
+		}
+		if err := g.AddDocument(centre, entity, documentID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BipartiteToUnipartite converts a bipartite graph to a unipartite graph by collapsing document links,
+// recording the documents that support each edge.
+// Documents with more than maxEntities entities (if maxEntities > 0) are handled using the policy.
+func BipartiteToUnipartite(connections *[]EntityDocument, maxEntities int, policy string) (*Graph, error) {
+
+	// Preconditions
+	if maxEntities < 0 {
+		return nil, fmt.Errorf("%w: maximum number of entities per document %v", ErrInvalidArgument, maxEntities)
+	}
+
+	if len(policy) == 0 {
+		policy = LargeDocumentSkip
+	}
+
+	if !validLargeDocumentPolicy(policy) {
+		return nil, fmt.Errorf("%w: policy for large documents %v", ErrInvalidArgument, policy)
+	}
+
+	// Map of document IDs to a set of entity IDs
+	docToEntities := make(map[string]*set.Set)
+
+	for _, conn := range *connections {
+
+		// If the document ID hasn't been seen, then add an empty Set
+		_, present := docToEntities[conn.DocumentID]
+		if !present {
+			docToEntities[conn.DocumentID] = set.New()
+		}
+
+		docToEntities[conn.DocumentID].Insert(conn.EntityID)
+	}
+
+	// Build the graph of just entities
+	g := NewGraph()
+
+	// Number of documents connecting the set number of entities
+	numOneEntity := 0
+	numTwoEntities := 0
+	numThreeOrMoreEntities := 0
+
+	// Number of documents exceeding the maximum number of entities per policy
+	numSkipped := 0
+	numStar := 0
+	numClique := 0
+
+	for docID, entIDs := range docToEntities {
+
+		if entIDs.Len() == 1 {
+			numOneEntity++
+			continue
+		}
+
+		elements := ConvertSetToSlice(entIDs)
+
+		// Documents within the limit are expanded to a clique
+		if maxEntities == 0 || len(elements) <= maxEntities {
+			if err := addClique(&g, docID, elements); err != nil {
+				return nil, err
+			}
+
+			if len(elements) == 2 {
+				numTwoEntities++
+			} else {
+				numThreeOrMoreEntities++
+			}
+			continue
+		}
+
+		// Apply the policy for large documents
+		var err error
+
+		switch policy {
+		case LargeDocumentSkip:
+			numSkipped++
+		case LargeDocumentStar:
+			err = addStar(&g, docID, elements)
+			numStar++
+		case LargeDocumentClique:
+			err = addClique(&g, docID, elements)
+			numClique++
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	log.Printf("Summary - Number of documents with 1 entity:    %v\n", numOneEntity)
+	log.Printf("Summary - Number of documents with 2 entities:  %v\n", numTwoEntities)
+	log.Printf("Summary - Number of documents with 3+ entities: %v\n", numThreeOrMoreEntities)
+	log.Printf("Summary - Number of large documents skipped:    %v\n", numSkipped)
+	log.Printf("Summary - Number of large documents as stars:   %v\n", numStar)
+	log.Printf("Summary - Number of large documents as cliques: %v\n", numClique)
+
+	return &g, nil
+}
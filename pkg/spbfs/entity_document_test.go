@@ -0,0 +1,601 @@
+package spbfs
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/golang-collections/collections/set"
+)
+
+func TestReadEntityDocumentGraphFromFile0(t *testing.T) {
+	filepath := "./test/test-data/entity_0.csv"
+	skipEntities := set.New()
+	result, err := ReadEntityDocumentGraphFromFile(filepath, skipEntities)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []EntityDocument{
+		EntityDocument{
+			EntityID:   "e-100",
+			DocumentID: "doc-4",
+		},
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Errorf("Expected %v, got %v\n", expected, result)
+	}
+}
+
+func TestReadEntityDocumentGraphFromFile1(t *testing.T) {
+	filepath := "./test/test-data/entity_1.csv"
+	skipEntities := set.New()
+	result, err := ReadEntityDocumentGraphFromFile(filepath, skipEntities)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []EntityDocument{
+		EntityDocument{
+			EntityID:   "e-100",
+			DocumentID: "doc-4",
+		},
+		EntityDocument{
+			EntityID:   "e-100",
+			DocumentID: "doc-1",
+		},
+		EntityDocument{
+			EntityID:   "e-100",
+			DocumentID: "doc-3",
+		},
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Errorf("Expected %v, got %v\n", expected, result)
+	}
+}
+
+func TestTestReadEntityDocumentGraphFromFile1WithSkip(t *testing.T) {
+	filepath := "./test/test-data/entity_3.csv"
+	skipEntities := set.New()
+	skipEntities.Insert("e-300")
+
+	result, err := ReadEntityDocumentGraphFromFile(filepath, skipEntities)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []EntityDocument{
+		EntityDocument{
+			EntityID:   "e-301",
+			DocumentID: "doc-4",
+		},
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Errorf("Expected %v, got %v\n", expected, result)
+	}
+}
+
+func TestTestReadEntityDocumentGraphFromFile2WithSkip(t *testing.T) {
+	filepath := "./test/test-data/entity_3.csv"
+	skipEntities := set.New()
+	skipEntities.Insert("e-300")
+	skipEntities.Insert("e-301")
+
+	result, err := ReadEntityDocumentGraphFromFile(filepath, skipEntities)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 0 {
+		t.Errorf("Expected list with no elements, got %v\n", len(result))
+	}
+
+}
+
+func TestReadEntityDocumentGraph(t *testing.T) {
+	filepaths := []string{
+		"./test/test-data/entity_2.csv",
+		"./test/test-data/entity_3.csv",
+	}
+	skipEntities := set.New()
+
+	result, err := ReadEntityDocumentGraph(filepaths, skipEntities)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []EntityDocument{
+		EntityDocument{
+			EntityID:   "e-200",
+			DocumentID: "doc-1",
+		},
+		EntityDocument{
+			EntityID:   "e-200",
+			DocumentID: "doc-2",
+		},
+		EntityDocument{
+			EntityID:   "e-300",
+			DocumentID: "doc-3",
+		},
+		EntityDocument{
+			EntityID:   "e-300",
+			DocumentID: "doc-2",
+		},
+		EntityDocument{
+			EntityID:   "e-301",
+			DocumentID: "doc-4",
+		},
+	}
+
+	if !reflect.DeepEqual(expected, *result) {
+		t.Errorf("Expected %v, got %v\n", expected, result)
+	}
+}
+
+func TestBipartiteToUnipartiteTwoEntities(t *testing.T) {
+	connections := []EntityDocument{
+		EntityDocument{
+			EntityID:   "e-1",
+			DocumentID: "d-1",
+		},
+		EntityDocument{
+			EntityID:   "e-2",
+			DocumentID: "d-1",
+		},
+	}
+
+	g, err := BipartiteToUnipartite(&connections, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual1 := g.AdjacentTo("e-1")
+	expected1 := []string{"e-2"}
+
+	if !reflect.DeepEqual(expected1, actual1) {
+		t.Errorf("Expected %v, got %v\n", expected1, actual1)
+	}
+
+	actual2 := g.AdjacentTo("e-2")
+	expected2 := []string{"e-1"}
+
+	if !reflect.DeepEqual(expected2, actual2) {
+		t.Errorf("Expected %v, got %v\n", expected2, actual2)
+	}
+}
+
+func TestBipartiteToUnipartiteThreeEntities(t *testing.T) {
+	// Note that e-1 is connected to d-3, but d-3 isn't connected to another entity
+	connections := []EntityDocument{
+		EntityDocument{
+			EntityID:   "e-1",
+			DocumentID: "d-1",
+		},
+		EntityDocument{
+			EntityID:   "e-2",
+			DocumentID: "d-1",
+		},
+		EntityDocument{
+			EntityID:   "e-1",
+			DocumentID: "d-3",
+		},
+	}
+
+	g, err := BipartiteToUnipartite(&connections, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual1 := g.AdjacentTo("e-1")
+	expected1 := []string{"e-2"}
+
+	if !reflect.DeepEqual(expected1, actual1) {
+		t.Errorf("Expected %v, got %v\n", expected1, actual1)
+	}
+
+	actual2 := g.AdjacentTo("e-2")
+	expected2 := []string{"e-1"}
+
+	if !reflect.DeepEqual(expected2, actual2) {
+		t.Errorf("Expected %v, got %v\n", expected2, actual2)
+	}
+}
+
+// largeDocumentConnections returns a document connecting four entities and a document connecting two
+func largeDocumentConnections() []EntityDocument {
+	return []EntityDocument{
+		{EntityID: "e-1", DocumentID: "d-1"},
+		{EntityID: "e-2", DocumentID: "d-1"},
+		{EntityID: "e-3", DocumentID: "d-1"},
+		{EntityID: "e-4", DocumentID: "d-1"},
+		{EntityID: "e-4", DocumentID: "d-2"},
+		{EntityID: "e-5", DocumentID: "d-2"},
+	}
+}
+
+func TestBipartiteToUnipartiteFourEntitiesNoLimit(t *testing.T) {
+	connections := largeDocumentConnections()
+
+	g, err := BipartiteToUnipartite(&connections, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := NewGraph()
+	expected.AddUndirected("e-1", "e-2")
+	expected.AddUndirected("e-1", "e-3")
+	expected.AddUndirected("e-1", "e-4")
+	expected.AddUndirected("e-2", "e-3")
+	expected.AddUndirected("e-2", "e-4")
+	expected.AddUndirected("e-3", "e-4")
+	expected.AddUndirected("e-4", "e-5")
+
+	if !g.Equal(&expected, true) {
+		t.Errorf("Expected a clique for the large document")
+	}
+}
+
+func TestBipartiteToUnipartiteLargeDocumentSkip(t *testing.T) {
+	connections := largeDocumentConnections()
+
+	g, err := BipartiteToUnipartite(&connections, 3, LargeDocumentSkip)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := NewGraph()
+	expected.AddUndirected("e-4", "e-5")
+
+	if !g.Equal(&expected, true) {
+		t.Errorf("Expected the large document to be skipped")
+	}
+}
+
+func TestBipartiteToUnipartiteLargeDocumentStar(t *testing.T) {
+	connections := largeDocumentConnections()
+
+	g, err := BipartiteToUnipartite(&connections, 3, LargeDocumentStar)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := NewGraph()
+	expected.AddUndirected(documentVertex("d-1"), "e-1")
+	expected.AddUndirected(documentVertex("d-1"), "e-2")
+	expected.AddUndirected(documentVertex("d-1"), "e-3")
+	expected.AddUndirected(documentVertex("d-1"), "e-4")
+	expected.AddUndirected("e-4", "e-5")
+
+	if !g.Equal(&expected, true) {
+		t.Errorf("Expected the large document to be a star")
+	}
+}
+
+// assertNoDocumentVertices checks that none of the vertices are virtual document vertices
+func assertNoDocumentVertices(t *testing.T, description string, vertices []string) {
+	t.Helper()
+
+	for _, vertex := range vertices {
+		if isDocumentVertex(vertex) {
+			t.Errorf("%v: didn't expect the virtual document vertex %q in %q\n", description, vertex, vertices)
+		}
+	}
+}
+
+func TestDocumentVerticesNotReported(t *testing.T) {
+	connections := largeDocumentConnections()
+
+	g, err := BipartiteToUnipartite(&connections, 3, LargeDocumentStar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := g.Freeze()
+
+	// The hop via the document is reported as a single hop supported by the document
+	outputConfig := OutputConfig{MaxDepth: 3}
+	results, err := findPathResults(context.Background(), c, "e-1", "set-1", "e-5", "set-2", outputConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := testPathResult(t, "e-1", "set-1", "e-5", "set-2", []string{"e-1", "e-4", "e-5"})
+	expected.Documents = [][]string{{"d-1"}, {"d-2"}}
+	expected.Cost = 3
+	expected.Mode = PathModeFirst
+
+	if !reflect.DeepEqual([]PathResult{expected}, results) {
+		t.Errorf("Expected %v, got %v\n", expected, results)
+	}
+
+	for _, mode := range []string{PathModeAllShortest, PathModeAllSimple, PathModeKShortest} {
+		outputConfig := OutputConfig{MaxDepth: 3, PathMode: mode, MaxPathsPerPair: 2}
+		results, err := findPathResults(context.Background(), c, "e-1", "set-1", "e-3", "set-2", outputConfig)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(results) == 0 {
+			t.Errorf("%v: expected a path\n", mode)
+		}
+
+		for _, result := range results {
+			assertNoDocumentVertices(t, mode, result.Path)
+		}
+	}
+
+	// The document vertex isn't one of the neighbours of an entity
+	unit := workUnit{
+		source:                "e-1",
+		sourceDataSource:      "set-1",
+		destinationDataSource: PairModeNeighbourhood,
+		neighbourhood:         true,
+	}
+
+	results, err = findNeighbourhoodResults(context.Background(), c, unit, set.New(), outputConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 4 {
+		t.Errorf("Expected 4 neighbours, got %v\n", results)
+	}
+
+	for _, result := range results {
+		assertNoDocumentVertices(t, "neighbourhood", append([]string{result.DestinationEntityID}, result.Path...))
+	}
+
+	// The subgraph has an edge for the hop via the document
+	s := NewSubgraph()
+	if err := s.Add(g.Freeze(), expected); err != nil {
+		t.Fatal(err)
+	}
+
+	assertNoDocumentVertices(t, "subgraph", s.vertices())
+
+	if documents := s.Graph.EdgeDocuments("e-1", "e-4"); !reflect.DeepEqual([]string{"d-1"}, documents) {
+		t.Errorf("Expected the hop to be supported by d-1, got %v\n", documents)
+	}
+
+	if weight := s.Graph.Weight("e-1", "e-4"); weight != 2 {
+		t.Errorf("Expected the hop to have a weight of 2, got %v\n", weight)
+	}
+}
+
+func TestStarDocumentPathModes(t *testing.T) {
+
+	// The small document d-3 also connects e-1 and e-2
+	connections := append(largeDocumentConnections(),
+		EntityDocument{EntityID: "e-1", DocumentID: "d-3"},
+		EntityDocument{EntityID: "e-2", DocumentID: "d-3"})
+
+	g, err := BipartiteToUnipartite(&connections, 3, LargeDocumentStar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := g.Freeze()
+
+	testCases := []struct {
+		outputConfig OutputConfig
+		destination  string
+		expected     [][]string
+	}{
+		{
+			// The hop via the document counts as one hop
+			outputConfig: OutputConfig{MaxDepth: 1},
+			destination:  "e-4",
+			expected:     [][]string{{"e-1", "e-4"}},
+		},
+		{
+			outputConfig: OutputConfig{MaxDepth: 2, Algorithm: AlgorithmBidirectional},
+			destination:  "e-5",
+			expected:     [][]string{{"e-1", "e-4", "e-5"}},
+		},
+		{
+			outputConfig: OutputConfig{MaxDepth: 2, Algorithm: AlgorithmDijkstra},
+			destination:  "e-5",
+			expected:     [][]string{{"e-1", "e-4", "e-5"}},
+		},
+		{
+			// The direct hop and the hop via the document aren't different paths
+			outputConfig: OutputConfig{MaxDepth: 2, PathMode: PathModeAllShortest},
+			destination:  "e-3",
+			expected:     [][]string{{"e-1", "e-3"}},
+		},
+		{
+			outputConfig: OutputConfig{MaxDepth: 2, PathMode: PathModeAllSimple},
+			destination:  "e-3",
+			expected:     [][]string{{"e-1", "e-3"}, {"e-1", "e-2", "e-3"}, {"e-1", "e-4", "e-3"}},
+		},
+		{
+			outputConfig: OutputConfig{MaxDepth: 2, PathMode: PathModeKShortest, MaxPathsPerPair: 3},
+			destination:  "e-3",
+			expected:     [][]string{{"e-1", "e-3"}, {"e-1", "e-2", "e-3"}, {"e-1", "e-4", "e-3"}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		description := testCase.outputConfig.pathMode() + " " + testCase.outputConfig.Algorithm
+
+		results, err := findPathResults(context.Background(), c, "e-1", "set-1", testCase.destination, "set-2", testCase.outputConfig)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		paths := [][]string{}
+		for _, result := range results {
+			paths = append(paths, result.Path)
+		}
+
+		if !reflect.DeepEqual(testCase.expected, paths) {
+			t.Errorf("%v: expected %v, got %v\n", description, testCase.expected, paths)
+		}
+	}
+
+	// The hop between e-1 and e-2 is supported by both documents
+	results, err := findPathResults(context.Background(), c, "e-1", "set-1", "e-2", "set-2", OutputConfig{MaxDepth: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 1 || !reflect.DeepEqual([][]string{{"d-1", "d-3"}}, results[0].Documents) || results[0].Cost != 1 {
+		t.Errorf("Expected a hop supported by d-1 and d-3 with a cost of 1, got %v\n", results)
+	}
+
+	// The neighbourhood depths count the hop via the document as one hop
+	unit := workUnit{
+		source:                "e-1",
+		sourceDataSource:      "set-1",
+		destinationDataSource: PairModeNeighbourhood,
+		neighbourhood:         true,
+	}
+
+	results, err = findNeighbourhoodResults(context.Background(), c, unit, set.New(), OutputConfig{MaxDepth: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	destinations := []string{}
+	for _, result := range results {
+		destinations = append(destinations, result.DestinationEntityID)
+	}
+
+	if expected := []string{"e-2", "e-3", "e-4"}; !reflect.DeepEqual(expected, destinations) {
+		t.Errorf("Expected %v, got %v\n", expected, destinations)
+	}
+}
+
+func TestWriteUndirectedEdgeListWithoutDocumentVertices(t *testing.T) {
+	connections := largeDocumentConnections()
+
+	g, err := BipartiteToUnipartite(&connections, 3, LargeDocumentStar)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "unipartite.csv")
+	if err := g.WriteUndirectedEdgeList(path, ","); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the edge between the entities is written
+	if content := string(readFile(t, path)); content != "e-4,e-5,d-2\n" {
+		t.Errorf("Expected only the edge e-4 -- e-5, got %q\n", content)
+	}
+}
+
+func TestReadInputFileDocumentVertex(t *testing.T) {
+	path := writeEdgeList(t, "entity_id,document_id\ne-1,d-1\n"+documentVertex("d-2")+",d-2\n")
+
+	if _, err := ReadInputFile(InputFile{Path: path}, set.New()); !errors.Is(err, ErrInvalidRow) {
+		t.Errorf("Expected %v, got %v\n", ErrInvalidRow, err)
+	}
+}
+
+func TestBipartiteToUnipartiteLargeDocumentClique(t *testing.T) {
+	connections := largeDocumentConnections()
+
+	g, err := BipartiteToUnipartite(&connections, 3, LargeDocumentClique)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := BipartiteToUnipartite(&connections, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !g.Equal(expected, true) {
+		t.Errorf("Expected the large document to be a clique")
+	}
+}
+
+func TestReadEntityDocumentGraphFromFileInvalid(t *testing.T) {
+	filepaths := []string{
+		"./test/test-data/entity_invalid_columns.csv",
+		"./test/test-data/entity_invalid_blank.csv",
+	}
+
+	for _, filepath := range filepaths {
+		if _, err := ReadEntityDocumentGraphFromFile(filepath, set.New()); !errors.Is(err, ErrInvalidRow) {
+			t.Errorf("%v: expected %v, got %v\n", filepath, ErrInvalidRow, err)
+		}
+	}
+}
+
+func TestReadInputFile(t *testing.T) {
+	hasHeader := false
+
+	testCases := []struct {
+		file     InputFile
+		expected []EntityDocument
+	}{
+		{
+			file: InputFile{Path: "./test/test-data/entity_schema.tsv", Delimiter: "\t",
+				EntityColumn: "entity", DocumentColumn: "doc_ref", Comment: "#"},
+			expected: []EntityDocument{
+				{EntityID: "e-100", DocumentID: "doc-1"},
+				{EntityID: "e-101", DocumentID: "doc-2"},
+				{EntityID: "e-100", DocumentID: "doc-2"},
+			},
+		},
+		{
+			file: InputFile{Path: "./test/test-data/entity_no_header.csv", HasHeader: &hasHeader},
+			expected: []EntityDocument{
+				{EntityID: "e-100", DocumentID: "doc-4"},
+				{EntityID: "e-101", DocumentID: "doc-4"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		result, err := ReadInputFile(testCase.file, set.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(testCase.expected, result) {
+			t.Errorf("%v: expected %v, got %v\n", testCase.file.Path, testCase.expected, result)
+		}
+	}
+}
+
+func TestReadInputFileInvalid(t *testing.T) {
+	testCases := []struct {
+		file     InputFile
+		expected error
+	}{
+		// Column names that aren't in the header
+		{InputFile{Path: "./test/test-data/entity_invalid_header.tsv", Delimiter: "\t",
+			EntityColumn: "entity", DocumentColumn: "doc_ref"}, ErrInvalidHeader},
+		// More than two columns without column names
+		{InputFile{Path: "./test/test-data/entity_invalid_header.tsv", Delimiter: "\t"}, ErrInvalidHeader},
+		// Comment lines aren't ignored by default
+		{InputFile{Path: "./test/test-data/entity_schema.tsv", Delimiter: "\t",
+			EntityColumn: "entity", DocumentColumn: "doc_ref"}, ErrInvalidHeader},
+		// Invalid layout
+		{InputFile{Path: "./test/test-data/entity_1.csv", Delimiter: "||"}, ErrInvalidArgument},
+	}
+
+	for _, testCase := range testCases {
+		if _, err := ReadInputFile(testCase.file, set.New()); !errors.Is(err, testCase.expected) {
+			t.Errorf("%v: expected %v, got %v\n", testCase.file.Path, testCase.expected, err)
+		}
+	}
+}
+
+func TestBipartiteToUnipartiteInvalidArguments(t *testing.T) {
+	connections := largeDocumentConnections()
+
+	if _, err := BipartiteToUnipartite(&connections, -1, ""); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("Expected %v, got %v\n", ErrInvalidArgument, err)
+	}
+
+	if _, err := BipartiteToUnipartite(&connections, 3, "unknown"); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("Expected %v, got %v\n", ErrInvalidArgument, err)
+	}
+}
@@ -0,0 +1,22 @@
+package spbfs
+
+import "errors"
+
+// Errors returned for invalid input. The errors returned by the functions wrap one of these with
+// the details, so they can be checked using errors.Is.
+var (
+	ErrEmptyVertex      = errors.New("vertex is empty")                               // a vertex or entity ID is blank
+	ErrSelfLoop         = errors.New("source and destination vertices are identical") // an edge from a vertex to itself
+	ErrRepeatedVertex   = errors.New("vertex is already on the path")                 // a vertex would appear twice on a path
+	ErrEmptyDocument    = errors.New("document ID is empty")                          // a document ID is blank
+	ErrNegativeWeight   = errors.New("edge weight is negative")                       // the cost of an edge is below zero
+	ErrInvalidDepth     = errors.New("invalid depth")                                 // a depth or number of hops is out of range
+	ErrInvalidCost      = errors.New("invalid cost")                                  // a maximum cost is below zero
+	ErrInvalidArgument  = errors.New("invalid argument")                              // any other argument is out of range
+	ErrEmptyPath        = errors.New("path is too short")                             // a path doesn't have enough vertices
+	ErrInvalidDelimiter = errors.New("invalid delimiter")                             // a delimiter isn't a single valid character
+	ErrInvalidRow       = errors.New("invalid row")                                   // a row of an input file can't be parsed
+	ErrInvalidHeader    = errors.New("invalid header")                                // the header of an input file doesn't match its layout
+	ErrConfig           = errors.New("invalid config")                                // the config can't be read or has an invalid option
+	ErrNoPairs          = errors.New("no pairs of entities to search")                // the config doesn't have any pairs of entities
+)
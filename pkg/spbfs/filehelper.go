@@ -1,19 +1,19 @@
-package main
+package spbfs
 
 import (
 	"bufio"
-	"log"
+	"fmt"
 	"os"
 )
 
 // ReadFileIntoSlice reads the contents of a file into a slice
-func ReadFileIntoSlice(filepath string) *[]string {
+func ReadFileIntoSlice(filepath string) (*[]string, error) {
 
 	// Open the file
 	file, err := os.Open(filepath)
 
 	if err != nil {
-		log.Fatalf("Failed to open %v\n", filepath)
+		return nil, fmt.Errorf("failed to open %v: %w", filepath, err)
 	}
 
 	defer file.Close()
@@ -27,29 +27,39 @@ func ReadFileIntoSlice(filepath string) *[]string {
 	}
 
 	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("failed to read %v: %w", filepath, err)
 	}
 
-	return &lines
+	return &lines, nil
 }
 
-// FilesHaveSameContentIgnoringOrder returns true if two files have the same content, but in any order
+// FilesHaveSameContentIgnoringOrder returns true if two files have the same content, but in any order.
+// Returns false if either file can't be read.
 func FilesHaveSameContentIgnoringOrder(filepath1 string, filepath2 string) bool {
 
 	// Read the contents of the file
-	contents1 := ReadFileIntoSlice(filepath1)
-	contents2 := ReadFileIntoSlice(filepath2)
+	contents1, err1 := ReadFileIntoSlice(filepath1)
+	contents2, err2 := ReadFileIntoSlice(filepath2)
+
+	if err1 != nil || err2 != nil {
+		return false
+	}
 
 	// Return if the two slices are the same, ignoring the order
 	return SlicesHaveSameElements(contents1, contents2)
 }
 
-// FilesHaveSameContent returns true if the files have the same content, considering the order of the rows
+// FilesHaveSameContent returns true if the files have the same content, considering the order of the rows.
+// Returns false if either file can't be read.
 func FilesHaveSameContent(filepath1 string, filepath2 string) bool {
 
 	// Read the contents of the file
-	contents1 := ReadFileIntoSlice(filepath1)
-	contents2 := ReadFileIntoSlice(filepath2)
+	contents1, err1 := ReadFileIntoSlice(filepath1)
+	contents2, err2 := ReadFileIntoSlice(filepath2)
+
+	if err1 != nil || err2 != nil {
+		return false
+	}
 
 	// Check the files contain the same number of rows
 	if len(*contents1) != len(*contents2) {
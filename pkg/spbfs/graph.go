@@ -0,0 +1,1301 @@
+package spbfs
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/golang-collections/collections/queue"
+	"github.com/golang-collections/collections/set"
+)
+
+// documentDelimiter separates the document IDs supporting a single edge
+const documentDelimiter = ";"
+
+// Graph represents a directed graph
+type Graph struct {
+	Nodes     map[string]*set.Set
+	Documents map[string]*set.Set // document IDs supporting each edge (see edgeKey)
+	Weights   map[string]float64  // cost of each edge if it isn't 1 (see edgeKey)
+}
+
+// NewGraph constructs a new Graph
+func NewGraph() Graph {
+	return Graph{
+		Nodes:     make(map[string]*set.Set),
+		Documents: make(map[string]*set.Set),
+		Weights:   make(map[string]float64),
+	}
+}
+
+// edgeKey returns the key for an edge that is independent of its direction
+func edgeKey(source string, destination string) string {
+	if source > destination {
+		source, destination = destination, source
+	}
+	return source + "\x00" + destination
+}
+
+// listOfKeys returns a list of keys from the Nodes data structure
+func (g *Graph) listOfKeys() []string {
+	keys := make([]string, len(g.Nodes))
+	i := 0
+
+	for k := range g.Nodes {
+		keys[i] = k
+		i++
+	}
+
+	return keys
+}
+
+// Equal determines if two graphs are equal
+func (g *Graph) Equal(g2 *Graph, debug bool) bool {
+
+	// Check the vertices
+	keys1 := g.listOfKeys()
+	keys2 := g2.listOfKeys()
+
+	if !SlicesHaveSameElements(&keys1, &keys2) {
+		if debug {
+			log.Println("Lists of keys are different")
+			log.Printf("Keys1: %v\n", keys1)
+			log.Printf("Keys2: %v\n", keys2)
+		}
+		return false
+	}
+
+	// Walk through each vertex and check its connections
+	for _, vertex := range keys1 {
+		conns1 := g.Nodes[vertex]
+		conns2 := g2.Nodes[vertex]
+
+		if !SetsEqual(conns1, conns2) {
+			if debug {
+				log.Printf("Connections different for vertex %v", vertex)
+				log.Printf("Connections 1: %v\n", conns1)
+				log.Printf("Connections 2: %v\n", conns2)
+			}
+			return false
+		}
+	}
+
+	return true
+}
+
+// AddDirected adds a directed connection in the graph
+func (g *Graph) AddDirected(source string, destination string) error {
+
+	// Preconditions
+	if source == destination {
+		return fmt.Errorf("%w: %v", ErrSelfLoop, source)
+	}
+
+	if len(source) == 0 {
+		return fmt.Errorf("%w: source of the edge to %v", ErrEmptyVertex, destination)
+	}
+
+	if len(destination) == 0 {
+		return fmt.Errorf("%w: destination of the edge from %v", ErrEmptyVertex, source)
+	}
+
+	// Has the source been seen before?
+	_, present := g.Nodes[source]
+	if !present {
+		g.Nodes[source] = set.New()
+	}
+
+	g.Nodes[source].Insert(destination)
+	return nil
+}
+
+// AddUndirected adds an undirected edge between source and destination vertices
+func (g *Graph) AddUndirected(source string, destination string) error {
+
+	if err := g.AddDirected(source, destination); err != nil {
+		return err
+	}
+
+	return g.AddDirected(destination, source)
+}
+
+// AddDocument records a document ID that supports the edge between source and destination vertices
+func (g *Graph) AddDocument(source string, destination string, documentID string) error {
+
+	// Precondition
+	if len(documentID) == 0 {
+		return fmt.Errorf("%w: edge %v -- %v", ErrEmptyDocument, source, destination)
+	}
+
+	key := edgeKey(source, destination)
+
+	_, present := g.Documents[key]
+	if !present {
+		g.Documents[key] = set.New()
+	}
+
+	g.Documents[key].Insert(documentID)
+	return nil
+}
+
+// HasDocuments returns true if the graph records the documents supporting its edges
+func (g *Graph) HasDocuments() bool {
+	return len(g.Documents) > 0
+}
+
+// EdgeDocuments returns the sorted document IDs supporting the edge between source and destination vertices
+func (g *Graph) EdgeDocuments(source string, destination string) []string {
+
+	documents, ok := g.Documents[edgeKey(source, destination)]
+	if !ok {
+		return []string{}
+	}
+
+	return ConvertSetToSlice(documents)
+}
+
+// hopDocuments returns the sorted document IDs supporting the hop between two entities, including
+// the documents whose virtual document vertex connects them
+func (g *Graph) hopDocuments(source string, destination string) []string {
+
+	documents := g.EdgeDocuments(source, destination)
+
+	shared := g.sharedDocumentVertices(source, destination)
+	if len(shared) == 0 {
+		return documents
+	}
+
+	for _, vertex := range shared {
+		documents = append(documents, strings.TrimPrefix(vertex, documentVertexPrefix))
+	}
+	sort.Strings(documents)
+
+	return documents
+}
+
+// PathDocuments returns the document IDs supporting each hop of a path
+func (g *Graph) PathDocuments(path []string) [][]string {
+
+	documents := [][]string{}
+
+	for i := 0; i < len(path)-1; i++ {
+		documents = append(documents, g.hopDocuments(path[i], path[i+1]))
+	}
+
+	return documents
+}
+
+// formatDocuments converts the document IDs for each hop to a delimited string
+func formatDocuments(documents [][]string, pathDelimiter string) string {
+
+	hops := make([]string, len(documents))
+
+	for i, hop := range documents {
+		hops[i] = strings.Join(hop, documentDelimiter)
+	}
+
+	return strings.Join(hops, pathDelimiter)
+}
+
+// SetWeight sets the cost of the edge between source and destination vertices
+func (g *Graph) SetWeight(source string, destination string, weight float64) error {
+
+	// Precondition
+	if weight < 0 {
+		return fmt.Errorf("%w: %v for edge %v -- %v", ErrNegativeWeight, weight, source, destination)
+	}
+
+	g.Weights[edgeKey(source, destination)] = weight
+	return nil
+}
+
+// Weight returns the cost of the edge between source and destination vertices (default 1)
+func (g *Graph) Weight(source string, destination string) float64 {
+
+	weight, ok := g.Weights[edgeKey(source, destination)]
+	if !ok {
+		return 1.0
+	}
+
+	return weight
+}
+
+// hopCost returns the cost of the hop between two entities, which is the lower of the cost of the
+// edge between them and the cost of the two edges via a virtual document vertex connecting them
+func (g *Graph) hopCost(source string, destination string) float64 {
+
+	cost := math.Inf(1)
+
+	if destinations, ok := g.Nodes[source]; ok && destinations.Has(destination) {
+		cost = g.Weight(source, destination)
+	}
+
+	for _, vertex := range g.sharedDocumentVertices(source, destination) {
+		cost = math.Min(cost, g.Weight(source, vertex)+g.Weight(vertex, destination))
+	}
+
+	return cost
+}
+
+// PathCost returns the total cost of the hops on a path
+func (g *Graph) PathCost(path []string) float64 {
+
+	cost := 0.0
+
+	for i := 0; i < len(path)-1; i++ {
+		cost += g.hopCost(path[i], path[i+1])
+	}
+
+	return cost
+}
+
+// AdjacentTo returns the vertices adjacent to a given vertex (none if the vertex isn't in the graph)
+func (g *Graph) AdjacentTo(source string) []string {
+
+	values, ok := g.Nodes[source]
+	if !ok {
+		return nil
+	}
+
+	return ConvertSetToSlice(values)
+}
+
+// entityNeighbours returns the entities one hop from an entity, which are the adjacent entities
+// and the entities in the documents whose virtual document vertex is adjacent (see
+// LargeDocumentStar). A document vertex is passed through rather than counted as a hop, so the
+// searches never return one. The document vertices sort before the entities, so the entities in
+// the documents come first.
+func (g *Graph) entityNeighbours(source string) []string {
+
+	adjacent := g.AdjacentTo(source)
+	if len(adjacent) == 0 || !isDocumentVertex(adjacent[0]) {
+		return adjacent
+	}
+
+	seen := set.New(source)
+	neighbours := []string{}
+
+	add := func(vertex string) {
+		if !seen.Has(vertex) {
+			seen.Insert(vertex)
+			neighbours = append(neighbours, vertex)
+		}
+	}
+
+	for _, vertex := range adjacent {
+		if !isDocumentVertex(vertex) {
+			add(vertex)
+			continue
+		}
+
+		for _, entity := range g.AdjacentTo(vertex) {
+			add(entity)
+		}
+	}
+
+	return neighbours
+}
+
+// hopCosts returns the cost of the hop to each of the entities one hop from an entity (see hopCost)
+func (g *Graph) hopCosts(source string) map[string]float64 {
+
+	costs := map[string]float64{}
+
+	keep := func(vertex string, cost float64) {
+		if previous, ok := costs[vertex]; !ok || cost < previous {
+			costs[vertex] = cost
+		}
+	}
+
+	for _, vertex := range g.AdjacentTo(source) {
+
+		cost := g.Weight(source, vertex)
+		if !isDocumentVertex(vertex) {
+			keep(vertex, cost)
+			continue
+		}
+
+		for _, entity := range g.AdjacentTo(vertex) {
+			if entity != source {
+				keep(entity, cost+g.Weight(vertex, entity))
+			}
+		}
+	}
+
+	return costs
+}
+
+// sharedDocumentVertices returns the virtual document vertices adjacent to both entities
+func (g *Graph) sharedDocumentVertices(source string, destination string) []string {
+
+	shared := []string{}
+
+	for _, vertex := range g.AdjacentTo(source) {
+
+		// The document vertices sort before the entities
+		if !isDocumentVertex(vertex) {
+			break
+		}
+
+		if destinations, ok := g.Nodes[vertex]; ok && destinations.Has(destination) {
+			shared = append(shared, vertex)
+		}
+	}
+
+	return shared
+}
+
+// Vertex represents a vertex in the graph
+type Vertex struct {
+	Identifier string
+	Depth      int
+	Cost       float64 // total cost of the edges from the root (weighted search only)
+	Parent     *Vertex
+}
+
+// NewVertex creates a new Vertex
+func NewVertex(identifier string, depth int) (Vertex, error) {
+
+	// Preconditions
+	if len(identifier) == 0 {
+		return Vertex{}, ErrEmptyVertex
+	}
+
+	if depth < 0 {
+		return Vertex{}, fmt.Errorf("%w: %v", ErrInvalidDepth, depth)
+	}
+
+	return makeVertex(identifier, depth), nil
+}
+
+// makeVertex makes a Vertex from an identifier already known to be valid
+func makeVertex(identifier string, depth int) Vertex {
+	return Vertex{
+		Identifier: identifier,
+		Depth:      depth,
+		Parent:     nil,
+	}
+}
+
+// checkSearch checks the arguments common to the searches from a root to a goal vertex
+func checkSearch(root string, goal string, maxDepth int) error {
+
+	if len(root) == 0 {
+		return fmt.Errorf("%w: root", ErrEmptyVertex)
+	}
+
+	if len(goal) == 0 {
+		return fmt.Errorf("%w: goal", ErrEmptyVertex)
+	}
+
+	if maxDepth < 0 {
+		return fmt.Errorf("%w: maximum depth %v", ErrInvalidDepth, maxDepth)
+	}
+
+	return nil
+}
+
+// Flatten the vertices to a single slice
+func (v *Vertex) flatten() []string {
+
+	lineage := []string{}
+
+	p := v
+	for p != nil {
+		// Prepend the lineage
+		lineage = append([]string{p.Identifier}, lineage...)
+		p = p.Parent
+	}
+
+	return lineage
+}
+
+// ReachableVertices finds all vertices reachable within m steps
+func (g *Graph) ReachableVertices(ctx context.Context, root string, maxDepth int) (bool, *set.Set, error) {
+
+	// Preconditions
+	if len(root) == 0 {
+		return false, nil, fmt.Errorf("%w: root", ErrEmptyVertex)
+	}
+
+	if maxDepth < 0 {
+		return false, nil, fmt.Errorf("%w: maximum depth %v", ErrInvalidDepth, maxDepth)
+	}
+
+	// Set of the identifiers of discovered vertices
+	discovered := set.New()
+	discovered.Insert(root)
+
+	// Check that the root vertex exists
+	_, present := g.Nodes[root]
+	if !present {
+		return false, nil, nil
+	}
+
+	// Queue to hold the vertices to visit
+	q := queue.New()
+	q.Enqueue(makeVertex(root, 0))
+
+	// While there are vertices in the queue to check
+	for q.Len() > 0 {
+
+		// Stop if the search has been cancelled or has run out of time
+		if err := ctx.Err(); err != nil {
+			return false, nil, err
+		}
+
+		// Take a vertex from the queue
+		v := q.Dequeue().(Vertex)
+
+		// Depth of any vertices adjacent to v
+		newDepth := v.Depth + 1
+
+		if newDepth <= maxDepth {
+
+			// Get a list of the adjacent vertices
+			w := g.entityNeighbours(v.Identifier)
+
+			// Walk through each adjacent vertex
+			for _, adjIdentifier := range w {
+
+				// If the vertex hasn't been seen before
+				if !discovered.Has(adjIdentifier) {
+
+					// Add the identifier to the set of discovered identifiers
+					discovered.Insert(adjIdentifier)
+
+					newVertex := makeVertex(adjIdentifier, newDepth)
+					newVertex.Parent = &v
+					q.Enqueue(newVertex)
+
+				}
+
+			}
+		}
+
+	}
+
+	return true, discovered, nil
+}
+
+// Bfs performs a Breadth First Search in the graph
+func (g *Graph) Bfs(ctx context.Context, root string, goal string, maxDepth int) (bool, *Vertex, error) {
+
+	// Preconditions
+	if err := checkSearch(root, goal, maxDepth); err != nil {
+		return false, nil, err
+	}
+
+	// Set of the identifiers of discovered vertices
+	discovered := set.New()
+	discovered.Insert(root)
+
+	// Queue to hold the vertices to visit
+	q := queue.New()
+	q.Enqueue(makeVertex(root, 0))
+
+	// While there are vertices in the queue to check
+	for q.Len() > 0 {
+
+		// Stop if the search has been cancelled or has run out of time
+		if err := ctx.Err(); err != nil {
+			return false, nil, err
+		}
+
+		// Take a vertex from the queue
+		v := q.Dequeue().(Vertex)
+
+		// If the vertex is the goal, then return
+		if v.Identifier == goal {
+			return true, &v, nil
+		}
+
+		// Depth of any vertices adjacent to v
+		newDepth := v.Depth + 1
+
+		// If the adjacent vertices are within the range
+		if newDepth <= maxDepth {
+
+			// Get a list of the adjacent vertices
+			w := g.entityNeighbours(v.Identifier)
+
+			// Walk through each of the adjacent vertices
+			for _, adjIdentifier := range w {
+
+				// If the vertex hasn't been seen before
+				if !discovered.Has(adjIdentifier) {
+
+					// Add the identifier to the set of discovered identifiers
+					discovered.Insert(adjIdentifier)
+
+					// Put the vertex on the queue
+					newVertex := makeVertex(adjIdentifier, newDepth)
+					newVertex.Parent = &v
+					q.Enqueue(newVertex)
+				}
+			}
+		}
+	}
+
+	// The goal was not found
+	return false, nil, nil
+}
+
+// vertexHeap is a priority queue of vertices ordered by cost, then depth, then identifier
+type vertexHeap []*Vertex
+
+func (h vertexHeap) Len() int { return len(h) }
+
+func (h vertexHeap) Less(i, j int) bool {
+	if h[i].Cost != h[j].Cost {
+		return h[i].Cost < h[j].Cost
+	}
+	if h[i].Depth != h[j].Depth {
+		return h[i].Depth < h[j].Depth
+	}
+	return h[i].Identifier < h[j].Identifier
+}
+
+func (h vertexHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *vertexHeap) Push(x interface{}) { *h = append(*h, x.(*Vertex)) }
+
+func (h *vertexHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	v := old[n-1]
+	*h = old[:n-1]
+	return v
+}
+
+// leastCostSearch runs Dijkstra's algorithm from the root, settling vertices up to the maximum
+// cost. The search stops when the goal is settled (if the goal isn't blank).
+func (g *Graph) leastCostSearch(ctx context.Context, root string, goal string, maxCost float64) (map[string]*Vertex, error) {
+
+	// Vertices whose least cost from the root is known
+	settled := make(map[string]*Vertex)
+
+	// Best known cost to each discovered vertex
+	best := map[string]float64{root: 0}
+
+	rootVertex := makeVertex(root, 0)
+	h := &vertexHeap{&rootVertex}
+
+	for h.Len() > 0 {
+
+		// Stop if the search has been cancelled or has run out of time
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// Take the vertex with the lowest cost
+		v := heap.Pop(h).(*Vertex)
+
+		// Ignore stale entries in the priority queue
+		if _, done := settled[v.Identifier]; done {
+			continue
+		}
+
+		settled[v.Identifier] = v
+
+		if v.Identifier == goal {
+			break
+		}
+
+		// Walk through each of the adjacent vertices
+		hopCosts := g.hopCosts(v.Identifier)
+
+		for _, adjIdentifier := range g.entityNeighbours(v.Identifier) {
+
+			if _, done := settled[adjIdentifier]; done {
+				continue
+			}
+
+			cost := v.Cost + hopCosts[adjIdentifier]
+			if cost > maxCost {
+				continue
+			}
+
+			// Only keep the first path found with the lowest cost
+			if previous, seen := best[adjIdentifier]; seen && previous <= cost {
+				continue
+			}
+
+			best[adjIdentifier] = cost
+
+			newVertex := makeVertex(adjIdentifier, v.Depth+1)
+			newVertex.Cost = cost
+			newVertex.Parent = v
+			heap.Push(h, &newVertex)
+		}
+	}
+
+	return settled, nil
+}
+
+// Dijkstra finds the least cost path from root to goal with a cost up to maxCost
+func (g *Graph) Dijkstra(ctx context.Context, root string, goal string, maxCost float64) (bool, *Vertex, error) {
+
+	// Preconditions
+	if len(root) == 0 {
+		return false, nil, fmt.Errorf("%w: root", ErrEmptyVertex)
+	}
+
+	if len(goal) == 0 {
+		return false, nil, fmt.Errorf("%w: goal", ErrEmptyVertex)
+	}
+
+	if maxCost < 0 {
+		return false, nil, fmt.Errorf("%w: maximum cost %v", ErrInvalidCost, maxCost)
+	}
+
+	// Check that the root vertex exists
+	_, present := g.Nodes[root]
+	if !present {
+		return false, nil, nil
+	}
+
+	settled, err := g.leastCostSearch(ctx, root, goal, maxCost)
+	if err != nil {
+		return false, nil, err
+	}
+
+	vertex, found := settled[goal]
+	if !found {
+		return false, nil, nil
+	}
+
+	return true, vertex, nil
+}
+
+// ReachableWithinCost finds all vertices reachable from the root with a cost up to maxCost
+func (g *Graph) ReachableWithinCost(ctx context.Context, root string, maxCost float64) (bool, *set.Set, error) {
+
+	// Preconditions
+	if len(root) == 0 {
+		return false, nil, fmt.Errorf("%w: root", ErrEmptyVertex)
+	}
+
+	if maxCost < 0 {
+		return false, nil, fmt.Errorf("%w: maximum cost %v", ErrInvalidCost, maxCost)
+	}
+
+	// Check that the root vertex exists
+	_, present := g.Nodes[root]
+	if !present {
+		return false, nil, nil
+	}
+
+	settled, err := g.leastCostSearch(ctx, root, "", maxCost)
+	if err != nil {
+		return false, nil, err
+	}
+
+	reachable := set.New()
+	for identifier := range settled {
+		reachable.Insert(identifier)
+	}
+
+	return true, reachable, nil
+}
+
+// expandFrontier expands a BFS frontier by one level, recording each newly discovered vertex in the
+// visited map. The meeting vertex (if any) minimises the depth in the other search.
+func (g *Graph) expandFrontier(frontier []*Vertex, visited map[string]*Vertex,
+	other map[string]*Vertex) ([]*Vertex, *Vertex) {
+
+	next := []*Vertex{}
+	var meeting *Vertex
+
+	for _, v := range frontier {
+
+		// Walk through each of the adjacent vertices
+		for _, adjIdentifier := range g.entityNeighbours(v.Identifier) {
+
+			// If the vertex hasn't been seen before from this side
+			if _, seen := visited[adjIdentifier]; seen {
+				continue
+			}
+
+			newVertex := makeVertex(adjIdentifier, v.Depth+1)
+			newVertex.Parent = v
+			visited[adjIdentifier] = &newVertex
+			next = append(next, &newVertex)
+
+			// Check whether the vertex has been reached by the other search
+			if o, found := other[adjIdentifier]; found {
+				if meeting == nil || o.Depth < other[meeting.Identifier].Depth {
+					meeting = &newVertex
+				}
+			}
+		}
+	}
+
+	return next, meeting
+}
+
+// joinLineages builds the path from the root to the goal via the vertex where the searches met
+func joinLineages(forward *Vertex, backward *Vertex) *Vertex {
+
+	// Vertices from the root to the meeting vertex, then on to the goal
+	path := forward.flatten()
+	for p := backward.Parent; p != nil; p = p.Parent {
+		path = append(path, p.Identifier)
+	}
+
+	// Rebuild the lineage from the root
+	var vertex *Vertex
+	for depth, identifier := range path {
+		v := makeVertex(identifier, depth)
+		v.Parent = vertex
+		vertex = &v
+	}
+
+	return vertex
+}
+
+// BidirectionalBfs performs a Breadth First Search from both the root and the goal, meeting in the middle.
+// It assumes the graph is undirected and returns a path with the same number of hops as Bfs.
+func (g *Graph) BidirectionalBfs(ctx context.Context, root string, goal string, maxDepth int) (bool, *Vertex, error) {
+
+	// Preconditions
+	if err := checkSearch(root, goal, maxDepth); err != nil {
+		return false, nil, err
+	}
+
+	// If the goal is the root, then return without traversing the graph
+	if root == goal {
+		v := makeVertex(root, 0)
+		return true, &v, nil
+	}
+
+	// Check that both vertices exist
+	_, rootPresent := g.Nodes[root]
+	_, goalPresent := g.Nodes[goal]
+	if !rootPresent || !goalPresent {
+		return false, nil, nil
+	}
+
+	// Vertices discovered from the root (forward) and from the goal (backward)
+	rootVertex := makeVertex(root, 0)
+	goalVertex := makeVertex(goal, 0)
+
+	forward := map[string]*Vertex{root: &rootVertex}
+	backward := map[string]*Vertex{goal: &goalVertex}
+
+	forwardFrontier := []*Vertex{&rootVertex}
+	backwardFrontier := []*Vertex{&goalVertex}
+
+	// Total number of hops covered by both searches
+	depth := 0
+
+	for depth < maxDepth && len(forwardFrontier) > 0 && len(backwardFrontier) > 0 {
+
+		// Stop if the search has been cancelled or has run out of time
+		if err := ctx.Err(); err != nil {
+			return false, nil, err
+		}
+
+		var meeting *Vertex
+
+		// Expand the smaller frontier
+		if len(forwardFrontier) <= len(backwardFrontier) {
+			forwardFrontier, meeting = g.expandFrontier(forwardFrontier, forward, backward)
+			if meeting != nil {
+				return true, joinLineages(meeting, backward[meeting.Identifier]), nil
+			}
+		} else {
+			backwardFrontier, meeting = g.expandFrontier(backwardFrontier, backward, forward)
+			if meeting != nil {
+				return true, joinLineages(forward[meeting.Identifier], meeting), nil
+			}
+		}
+
+		depth++
+	}
+
+	// The goal was not found
+	return false, nil, nil
+}
+
+// flattenAll flattens all of the tree nodes
+func flattenAll(paths []*TreeNode) [][]string {
+
+	flattened := [][]string{}
+
+	for _, node := range paths {
+		flattened = append(flattened, node.flatten())
+	}
+
+	return flattened
+}
+
+// AllPaths finds all the paths from root to goal up to a maximum depth
+func (g *Graph) AllPaths(ctx context.Context, root string, goal string, maxDepth int) ([]*TreeNode, error) {
+
+	// Preconditions
+	if err := checkSearch(root, goal, maxDepth); err != nil {
+		return nil, err
+	}
+
+	// Number of steps traversed from the root vertex
+	numSteps := 0
+
+	// If the goal is the root, then return without traversing the graph
+	treeNode := makeTreeNode(root, root == goal)
+	if treeNode.marked {
+		return []*TreeNode{treeNode}, nil
+	}
+
+	// Nodes to 'spider' from
+	qCurrent := queue.New()
+	qCurrent.Enqueue(treeNode)
+
+	// Nodes to 'spider' from on the next iteration
+	qNext := queue.New()
+
+	// List of complete nodes (where goal has been found)
+	complete := []*TreeNode{}
+
+	for numSteps < maxDepth {
+
+		for qCurrent.Len() > 0 {
+
+			// Stop if the search has been cancelled or has run out of time
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			// Take a tree node from the queue representing a vertex
+			node := qCurrent.Dequeue().(*TreeNode)
+
+			if node.marked {
+				return nil, fmt.Errorf("trying to traverse from a marked node: %v", node.name)
+			}
+
+			// Get a list of the adjacent vertices
+			w := g.entityNeighbours(node.name)
+
+			// Walk through each of the adjacent vertices
+			for _, adjIdentifier := range w {
+
+				if !node.containsVertex(adjIdentifier) {
+
+					marked := adjIdentifier == goal
+					child, err := node.makeChild(adjIdentifier, marked)
+					if err != nil {
+						return nil, err
+					}
+
+					if marked {
+						complete = append(complete, child)
+					} else {
+						qNext.Enqueue(child)
+					}
+				}
+
+			}
+		}
+
+		qCurrent = qNext
+		qNext = queue.New()
+		numSteps++
+
+	}
+
+	return complete, nil
+}
+
+// AllShortestPaths finds every path from root to goal with the minimum number of hops, up to a
+// maximum depth. The paths are found from the predecessors of each vertex in the BFS DAG.
+func (g *Graph) AllShortestPaths(ctx context.Context, root string, goal string, maxDepth int) ([][]string, error) {
+
+	// Preconditions
+	if err := checkSearch(root, goal, maxDepth); err != nil {
+		return nil, err
+	}
+
+	// If the goal is the root, then return without traversing the graph
+	if root == goal {
+		return [][]string{{root}}, nil
+	}
+
+	// Check that the root vertex exists
+	_, present := g.Nodes[root]
+	if !present {
+		return [][]string{}, nil
+	}
+
+	// Depth of each discovered vertex, the vertices preceding it on a shortest path and the
+	// number of shortest paths from the root to it
+	depth := map[string]int{root: 0}
+	predecessors := map[string][]string{}
+	numPaths := map[string]int{root: 1}
+
+	current := []string{root}
+
+	// Walk through the graph level by level until the goal is discovered
+	for d := 0; d < maxDepth && len(current) > 0; d++ {
+
+		// Stop if the search has been cancelled or has run out of time
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		next := []string{}
+
+		for _, v := range current {
+			for _, adjIdentifier := range g.entityNeighbours(v) {
+
+				adjDepth, seen := depth[adjIdentifier]
+
+				if !seen {
+					depth[adjIdentifier] = d + 1
+					next = append(next, adjIdentifier)
+				} else if adjDepth != d+1 {
+					// The vertex was discovered on an earlier level, so this isn't a shortest path
+					continue
+				}
+
+				predecessors[adjIdentifier] = append(predecessors[adjIdentifier], v)
+				numPaths[adjIdentifier] += numPaths[v]
+			}
+		}
+
+		// All of the shortest paths to the goal have been found
+		if _, found := depth[goal]; found {
+			break
+		}
+
+		current = next
+	}
+
+	// The goal was not found
+	if _, found := depth[goal]; !found {
+		return [][]string{}, nil
+	}
+
+	// Walk backwards from the goal through the predecessors to build each path
+	paths := make([][]string, 0, numPaths[goal])
+
+	var walk func(vertex string, suffix []string)
+	walk = func(vertex string, suffix []string) {
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		path := append([]string{vertex}, suffix...)
+
+		if vertex == root {
+			paths = append(paths, path)
+			return
+		}
+
+		for _, p := range predecessors[vertex] {
+			walk(p, path)
+		}
+	}
+
+	walk(goal, []string{})
+
+	// The walk may have been too long
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Sort the paths so that the order is deterministic
+	sort.Slice(paths, func(i, j int) bool {
+		return lessPath(paths[i], paths[j])
+	})
+
+	return paths, nil
+}
+
+// bfsAvoiding performs a Breadth First Search that doesn't use the removed vertices or directed edges
+func (g *Graph) bfsAvoiding(ctx context.Context, root string, goal string, maxDepth int,
+	removedVertices *set.Set, removedEdges *set.Set) ([]string, error) {
+
+	// Set of the identifiers of discovered vertices
+	discovered := set.New()
+	discovered.Insert(root)
+
+	// Queue to hold the vertices to visit
+	q := queue.New()
+	q.Enqueue(makeVertex(root, 0))
+
+	for q.Len() > 0 {
+
+		// Stop if the search has been cancelled or has run out of time
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		v := q.Dequeue().(Vertex)
+
+		if v.Identifier == goal {
+			return v.flatten(), nil
+		}
+
+		newDepth := v.Depth + 1
+		if newDepth > maxDepth {
+			continue
+		}
+
+		for _, adjIdentifier := range g.entityNeighbours(v.Identifier) {
+
+			if discovered.Has(adjIdentifier) || removedVertices.Has(adjIdentifier) ||
+				removedEdges.Has(v.Identifier+"\x00"+adjIdentifier) {
+				continue
+			}
+
+			discovered.Insert(adjIdentifier)
+
+			newVertex := makeVertex(adjIdentifier, newDepth)
+			newVertex.Parent = &v
+			q.Enqueue(newVertex)
+		}
+	}
+
+	return nil, nil
+}
+
+// samePath returns true if two paths contain the same vertices in the same order
+func samePath(p1 []string, p2 []string) bool {
+
+	if len(p1) != len(p2) {
+		return false
+	}
+
+	for i := range p1 {
+		if p1[i] != p2[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// lessPath orders paths by the number of hops and then alphabetically
+func lessPath(p1 []string, p2 []string) bool {
+
+	if len(p1) != len(p2) {
+		return len(p1) < len(p2)
+	}
+
+	for i := range p1 {
+		if p1[i] != p2[i] {
+			return p1[i] < p2[i]
+		}
+	}
+
+	return false
+}
+
+// KShortestPaths finds up to k loopless paths from root to goal with the fewest hops, up to a
+// maximum depth, using Yen's algorithm. The paths are returned in order of the number of hops.
+func (g *Graph) KShortestPaths(ctx context.Context, root string, goal string, k int, maxDepth int) ([][]string, error) {
+
+	// Preconditions
+	if k < 1 {
+		return nil, fmt.Errorf("%w: number of paths %v", ErrInvalidArgument, k)
+	}
+
+	// Shortest path
+	found, vertex, err := g.Bfs(ctx, root, goal, maxDepth)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return [][]string{}, nil
+	}
+
+	return yen(ctx, vertex.flatten(), goal, k, maxDepth, g.bfsAvoiding)
+}
+
+// avoidingSearch finds a shortest path from root to goal that doesn't use the removed vertices or
+// directed edges (nil if there isn't one)
+type avoidingSearch func(ctx context.Context, root string, goal string, maxDepth int,
+	removedVertices *set.Set, removedEdges *set.Set) ([]string, error)
+
+// yen finds up to k loopless paths to the goal, starting from the shortest path, using Yen's
+// algorithm. The spur paths are found using the search.
+func yen(ctx context.Context, shortest []string, goal string, k int, maxDepth int,
+	search avoidingSearch) ([][]string, error) {
+
+	// Accepted paths and candidate paths
+	accepted := [][]string{shortest}
+	candidates := [][]string{}
+
+	// Paths that have already been accepted or are candidates
+	seen := set.New()
+	seen.Insert(strings.Join(accepted[0], "\x00"))
+
+	for len(accepted) < k {
+
+		// Stop if the search has been cancelled or has run out of time
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		previous := accepted[len(accepted)-1]
+
+		// Each vertex on the previous path (except the goal) is a spur vertex
+		for i := 0; i < len(previous)-1; i++ {
+
+			spur := previous[i]
+			rootPath := previous[:i+1]
+
+			// Remove the edges from the spur vertex used by accepted paths with the same root path
+			removedEdges := set.New()
+			for _, path := range accepted {
+				if len(path) > i+1 && samePath(path[:i+1], rootPath) {
+					removedEdges.Insert(path[i] + "\x00" + path[i+1])
+				}
+			}
+
+			// Remove the vertices on the root path (except the spur) so that paths are loopless
+			removedVertices := SliceToSet(rootPath[:i])
+
+			spurPath, err := search(ctx, spur, goal, maxDepth-i, removedVertices, removedEdges)
+			if err != nil {
+				return nil, err
+			}
+
+			if spurPath == nil {
+				continue
+			}
+
+			// Join the root path and the spur path
+			path := append(append([]string{}, rootPath[:i]...), spurPath...)
+
+			key := strings.Join(path, "\x00")
+			if !seen.Has(key) {
+				seen.Insert(key)
+				candidates = append(candidates, path)
+			}
+		}
+
+		if len(candidates) == 0 {
+			break
+		}
+
+		// Accept the best candidate
+		sort.Slice(candidates, func(i, j int) bool {
+			return lessPath(candidates[i], candidates[j])
+		})
+
+		accepted = append(accepted, candidates[0])
+		candidates = candidates[1:]
+	}
+
+	return accepted, nil
+}
+
+// WriteEdgeList writes the edge list to a file with the required delimiter. If the graph
+// records the documents supporting each edge, then these are written in a third column. The
+// edges of the virtual document vertices (see LargeDocumentStar) aren't written, as they aren't
+// entities.
+func (g *Graph) WriteEdgeList(filepath string, delimiter string) error {
+
+	// Open the output CSV file for writing
+	outputFile, err := os.Create(filepath)
+	if err != nil {
+		return fmt.Errorf("unable to open output file %v for writing: %w", filepath, err)
+	}
+	defer outputFile.Close()
+
+	// Fields are quoted as required
+	writer, err := NewCSVWriter(outputFile, delimiter)
+	if err != nil {
+		return err
+	}
+
+	// Number of edges of virtual document vertices that weren't written
+	numSkipped := 0
+
+	// Walk through the source vertices
+	for source, destinations := range g.Nodes {
+
+		// Walk through the set of destination vertices
+		destinations.Do(func(s interface{}) {
+
+			// Destination as a string
+			d := s.(string)
+
+			if isDocumentVertex(source) || isDocumentVertex(d) {
+				numSkipped++
+				return
+			}
+
+			// Add the connection to the output file
+			parts := []string{source, d}
+			if g.HasDocuments() {
+				parts = append(parts, strings.Join(g.EdgeDocuments(source, d), documentDelimiter))
+			}
+			writer.Write(parts)
+		})
+	}
+
+	if numSkipped > 0 {
+		log.Printf("Didn't write %v edges of large documents to %v\n", numSkipped, filepath)
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("unable to write to output file %v: %w", filepath, err)
+	}
+
+	return nil
+}
+
+// SimplifyForUndirectedGraph simplifies the graph for undirected graphs
+func (g *Graph) SimplifyForUndirectedGraph() *Graph {
+
+	// Initialise a new graph
+	gUndirected := NewGraph()
+
+	// Walk though each node
+	for source, destinations := range g.Nodes {
+
+		// Walk through each destination vertex
+		destinations.Do(func(s interface{}) {
+
+			// Destination as a string
+			d := s.(string)
+
+			// If the destination vertex comes after the source vertex then
+			// add it to the simplified graph
+			if d > source {
+				// The edge is from a valid graph, so it doesn't need to be checked
+				if _, present := gUndirected.Nodes[source]; !present {
+					gUndirected.Nodes[source] = set.New()
+				}
+				gUndirected.Nodes[source].Insert(d)
+
+				// Retain the documents supporting the edge
+				if documents, ok := g.Documents[edgeKey(source, d)]; ok {
+					gUndirected.Documents[edgeKey(source, d)] = documents
+				}
+			}
+		})
+	}
+
+	return &gUndirected
+}
+
+// WriteUndirectedEdgeList creates an edge list for an undirected graph
+func (g *Graph) WriteUndirectedEdgeList(filepath string, delimiter string) error {
+
+	// Simplify the graph
+	simplified := g.SimplifyForUndirectedGraph()
+
+	// Write the edge lists to a file
+	return simplified.WriteEdgeList(filepath, delimiter)
+}
@@ -0,0 +1,1464 @@
+package spbfs
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang-collections/collections/set"
+)
+
+func TestEmptyGraph(t *testing.T) {
+	g := NewGraph()
+	if len(g.Nodes) != 0 {
+		t.Errorf("Expected 0 nodes, got %v\n", len(g.Nodes))
+	}
+}
+
+func TestAddDirectedOneEdge(t *testing.T) {
+	g := NewGraph()
+	g.AddDirected("s1", "d1")
+
+	if len(g.Nodes) != 1 {
+		t.Fatalf("Expected 1 node, got %v\n", len(g.Nodes))
+	}
+
+	expected := set.New("d1")
+	if !reflect.DeepEqual(g.Nodes["s1"], expected) {
+		t.Errorf("Expected %v, got %v", expected, g.Nodes["s1"])
+	}
+}
+
+func TestAddDirectedTwoEdges(t *testing.T) {
+	g := NewGraph()
+	g.AddDirected("s1", "d1")
+	g.AddDirected("s1", "d2")
+
+	if len(g.Nodes) != 1 {
+		t.Fatalf("Expected 1 node, got %v\n", len(g.Nodes))
+	}
+
+	expected := set.New("d1", "d2")
+	if !reflect.DeepEqual(g.Nodes["s1"], expected) {
+		t.Errorf("Expected %v, got %v", expected, g.Nodes["s1"])
+	}
+}
+
+func TestAddDirectedTwoEdgesDifferent(t *testing.T) {
+	g := NewGraph()
+	g.AddDirected("s1", "d1")
+	g.AddDirected("s2", "d2")
+
+	if len(g.Nodes) != 2 {
+		t.Fatalf("Expected 2 nodes, got %v\n", len(g.Nodes))
+	}
+
+	expected1 := set.New("d1")
+	if !reflect.DeepEqual(g.Nodes["s1"], expected1) {
+		t.Errorf("Expected %v, got %v", expected1, g.Nodes["s1"])
+	}
+
+	expected2 := set.New("d2")
+	if !reflect.DeepEqual(g.Nodes["s2"], expected2) {
+		t.Errorf("Expected %v, got %v", expected2, g.Nodes["s2"])
+	}
+}
+
+func TestAddUndirected(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("s1", "s2")
+
+	if len(g.Nodes) != 2 {
+		t.Errorf("Expected 2 nodes, got %v\n", len(g.Nodes))
+	}
+
+	expected1 := set.New("s2")
+	if !reflect.DeepEqual(g.Nodes["s1"], expected1) {
+		t.Errorf("Expected %v, got %v", expected1, g.Nodes["s1"])
+	}
+
+	expected2 := set.New("s1")
+	if !reflect.DeepEqual(g.Nodes["s2"], expected2) {
+		t.Errorf("Expected %v, got %v", expected2, g.Nodes["s2"])
+	}
+}
+
+func TestAdjacentToNotPresent(t *testing.T) {
+	g := NewGraph()
+	actual := g.AdjacentTo("node")
+
+	if len(actual) != 0 {
+		t.Errorf("Expected 0 nodes, got %v", actual)
+	}
+}
+
+func TestAdjacentToOneNode(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+
+	actualFromA := g.AdjacentTo("a")
+	expectedFromA := []string{"b"}
+	if !reflect.DeepEqual(expectedFromA, actualFromA) {
+		t.Errorf("Expected %v, got %v\n", expectedFromA, actualFromA)
+	}
+
+	actualFromB := g.AdjacentTo("b")
+	expectedFromB := []string{"a"}
+	if !reflect.DeepEqual(expectedFromB, actualFromB) {
+		t.Errorf("Expected %v, got %v\n", expectedFromB, actualFromB)
+	}
+}
+
+func TestAdjacentToTwoNodes(t *testing.T) {
+	g := NewGraph()
+	g.AddDirected("a", "b")
+	g.AddDirected("a", "c")
+	g.AddDirected("d", "f")
+
+	actual := g.AdjacentTo("a")
+	expected := []string{"b", "c"}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestFlattenOneVertex(t *testing.T) {
+	v1, err := NewVertex("vertex-1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual := v1.flatten()
+	expected := []string{"vertex-1"}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestFlattenTwoVertices(t *testing.T) {
+	v1, err := NewVertex("vertex-1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v2, err := NewVertex("vertex-2", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v2.Parent = &v1
+
+	actual := v2.flatten()
+	expected := []string{"vertex-1", "vertex-2"}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestFlattenThreeVertices(t *testing.T) {
+	v1, err := NewVertex("vertex-1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v2, err := NewVertex("vertex-2", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v2.Parent = &v1
+	v3, err := NewVertex("vertex-3", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v3.Parent = &v2
+
+	actual := v3.flatten()
+	expected := []string{"vertex-1", "vertex-2", "vertex-3"}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestBfsRootNodeNotPresent(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+
+	found, _, err := g.Bfs(context.Background(), "c", "a", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if found {
+		t.Errorf("Expected not to find the vertex")
+	}
+}
+
+func TestBfsGoalNodeNotPresent(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "d")
+
+	found, _, err := g.Bfs(context.Background(), "a", "b", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if found {
+		t.Errorf("Expected not to find the vertex")
+	}
+}
+
+func TestBfsTwoVertices(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+
+	found, vertex, err := g.Bfs(context.Background(), "a", "b", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !found {
+		t.Errorf("Expected to find the vertex")
+	}
+
+	actual := vertex.flatten()
+	expected := []string{"a", "b"}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestBfsThreeVertices(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("b", "c")
+
+	// a -> b
+	found, vertex, err := g.Bfs(context.Background(), "a", "b", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !found {
+		t.Errorf("Expected to find the vertex")
+	}
+
+	actual := vertex.flatten()
+	expected := []string{"a", "b"}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, got %v\n", expected, actual)
+	}
+
+	// a -> b -> c
+	found, vertex, err = g.Bfs(context.Background(), "a", "c", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !found {
+		t.Errorf("Expected to find the vertex")
+	}
+
+	actual = vertex.flatten()
+	expected = []string{"a", "b", "c"}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, got %v\n", expected, actual)
+	}
+
+	// a -> b -> c (but stops searching at 1)
+	found, vertex, err = g.Bfs(context.Background(), "a", "c", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if found {
+		t.Errorf("Expected not to find the vertex")
+	}
+}
+
+func TestBfsThreeVerticesCompletelyConnected(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("a", "c")
+	g.AddUndirected("b", "c")
+
+	// a -> b
+	found, vertex, err := g.Bfs(context.Background(), "a", "b", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !found {
+		t.Errorf("Expected to find the vertex")
+	}
+
+	actual := vertex.flatten()
+	expected := []string{"a", "b"}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestBfsDiamondShape(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("a", "c")
+	g.AddUndirected("b", "d")
+	g.AddUndirected("c", "d")
+
+	// a -> b -> d
+	found, vertex, err := g.Bfs(context.Background(), "a", "d", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !found {
+		t.Errorf("Expected to find the vertex")
+	}
+
+	actual := vertex.flatten()
+	expected := []string{"a", "b", "d"}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestBfsFourVerticesTwoConnectedComponents(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("c", "d")
+
+	found, vertex, err := g.Bfs(context.Background(), "a", "b", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !found {
+		t.Errorf("Expected to find the vertex")
+	}
+
+	actual := vertex.flatten()
+	expected := []string{"a", "b"}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, got %v\n", expected, actual)
+	}
+
+	found, vertex, err = g.Bfs(context.Background(), "a", "d", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if found {
+		t.Errorf("Expected not to find the vertex")
+	}
+}
+
+func TestReachableVerticesRootNotFound(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+
+	found, _, err := g.ReachableVertices(context.Background(), "c", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if found {
+		t.Errorf("Found vertex, didn't expect to\n")
+	}
+}
+
+func TestReachableVerticesZeroSteps(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("b", "c")
+	g.AddUndirected("c", "d")
+
+	found, actual, err := g.ReachableVertices(context.Background(), "a", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !found {
+		t.Fatalf("Expected to find vertex\n")
+	}
+
+	expected := set.New()
+	expected.Insert("a")
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, found %v\n", expected, actual)
+	}
+}
+
+func TestReachableVerticesOneStep(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("b", "c")
+	g.AddUndirected("c", "d")
+
+	found, actual, err := g.ReachableVertices(context.Background(), "a", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !found {
+		t.Fatalf("Expected to find vertex\n")
+	}
+
+	expected := set.New()
+	expected.Insert("a")
+	expected.Insert("b")
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, found %v\n", expected, actual)
+	}
+}
+
+func TestReachableVerticesTwoSteps(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("b", "c")
+	g.AddUndirected("c", "d")
+
+	found, actual, err := g.ReachableVertices(context.Background(), "a", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !found {
+		t.Fatalf("Expected to find vertex\n")
+	}
+
+	expected := set.New()
+	expected.Insert("a")
+	expected.Insert("b")
+	expected.Insert("c")
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, found %v\n", expected, actual)
+	}
+}
+
+func TestSimplifyForUndirectedGraph(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+
+	actual := g.SimplifyForUndirectedGraph()
+
+	expected := NewGraph()
+	expected.AddDirected("a", "b")
+
+	if !actual.Equal(&expected, false) {
+		t.Errorf("Expected actual graph to be equal to the expected graph")
+	}
+}
+
+func TestSimplifyForUndirectedGraph2(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("a", "c")
+
+	actual := g.SimplifyForUndirectedGraph()
+
+	expected := NewGraph()
+	expected.AddDirected("a", "b")
+	expected.AddDirected("a", "c")
+
+	if !actual.Equal(&expected, false) {
+		t.Errorf("Expected actual graph to be equal to the expected graph")
+	}
+}
+
+func TestSimplifyForUndirectedGraph3(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("b", "a")
+	g.AddUndirected("a", "c")
+
+	actual := g.SimplifyForUndirectedGraph()
+
+	expected := NewGraph()
+	expected.AddDirected("a", "b")
+	expected.AddDirected("a", "c")
+
+	if !actual.Equal(&expected, false) {
+		t.Errorf("Expected actual graph to be equal to the expected graph")
+	}
+}
+
+func TestSimplifyForUndirectedGraph4(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("a", "c")
+	g.AddUndirected("d", "a")
+
+	actual := g.SimplifyForUndirectedGraph()
+
+	expected := NewGraph()
+	expected.AddDirected("a", "b")
+	expected.AddDirected("a", "c")
+	expected.AddDirected("a", "d")
+
+	if !actual.Equal(&expected, false) {
+		t.Errorf("Expected actual graph to be equal to the expected graph")
+	}
+}
+
+func TestWriteEdgeList(t *testing.T) {
+
+	// Construct a test directed graph
+	g := NewGraph()
+	g.AddDirected("a", "b")
+	g.AddDirected("a", "c")
+	g.AddDirected("b", "d")
+	g.AddDirected("d", "e")
+
+	// Write the graph to a text file
+	actualFilepath := "./test/test-writing/actual-output-1.csv"
+	g.WriteEdgeList(actualFilepath, ",")
+
+	// Check the result
+	if !FilesHaveSameContentIgnoringOrder(actualFilepath, "./test/test-writing/expected-output-1.csv") {
+		t.Fatalf("Actual results differ from expected results\n")
+	}
+}
+
+func TestWriteUndirectedEdgeList(t *testing.T) {
+
+	// Construct a test undirected graph
+	g := NewGraph()
+	g.AddUndirected("b", "a")
+	g.AddUndirected("b", "d")
+	g.AddUndirected("c", "b")
+	g.AddUndirected("c", "e")
+	g.AddUndirected("c", "f")
+
+	// Write the graph to a text file
+	actualFilepath := "./test/test-writing/actual-output-2.csv"
+	g.WriteUndirectedEdgeList(actualFilepath, ",")
+
+	// Check the result
+	if !FilesHaveSameContentIgnoringOrder(actualFilepath, "./test/test-writing/expected-output-2.csv") {
+		t.Fatalf("Actual results differ from expected results\n")
+	}
+}
+
+func TestAllPaths2Vertices(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+
+	paths, err := g.AllPaths(context.Background(), "a", "b", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actualPaths := flattenAll(paths)
+
+	expectedPaths := [][]string{
+		{"a", "b"},
+	}
+
+	if !reflect.DeepEqual(expectedPaths, actualPaths) {
+		t.Errorf("Expected %v, got %v", expectedPaths, actualPaths)
+	}
+}
+
+func TestAllPaths3Vertices(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("b", "c")
+
+	// Stop too early
+	pathsStopped, err := g.AllPaths(context.Background(), "a", "c", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pathsStopped) > 0 {
+		t.Errorf("Didn't expect a path, found %v paths", len(pathsStopped))
+	}
+
+	// Stop after 2 steps
+	paths, err := g.AllPaths(context.Background(), "a", "c", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actualPaths := flattenAll(paths)
+
+	expectedPaths := [][]string{
+		{"a", "b", "c"},
+	}
+
+	if !reflect.DeepEqual(expectedPaths, actualPaths) {
+		t.Errorf("Expected %v, got %v", expectedPaths, actualPaths)
+	}
+}
+
+func TestAllPaths4Vertices(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("b", "d")
+	g.AddUndirected("a", "c")
+	g.AddUndirected("c", "d")
+
+	// Stop too early
+	pathsStopped, err := g.AllPaths(context.Background(), "a", "d", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pathsStopped) > 0 {
+		t.Errorf("Didn't expect a path, found %v paths", len(pathsStopped))
+	}
+
+	// Stop after 2 steps
+	paths, err := g.AllPaths(context.Background(), "a", "d", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actualPaths := flattenAll(paths)
+
+	expectedPaths := [][]string{
+		{"a", "b", "d"},
+		{"a", "c", "d"},
+	}
+
+	if !reflect.DeepEqual(expectedPaths, actualPaths) {
+		t.Errorf("Expected %v, got %v", expectedPaths, actualPaths)
+	}
+}
+
+func TestAllPaths6Vertices(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("b", "c")
+	g.AddUndirected("b", "d")
+	g.AddUndirected("c", "e")
+	g.AddUndirected("d", "e")
+	g.AddUndirected("e", "f")
+
+	paths, err := g.AllPaths(context.Background(), "a", "e", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actualPaths := flattenAll(paths)
+
+	expectedPaths := [][]string{
+		{"a", "b", "c", "e"},
+		{"a", "b", "d", "e"},
+	}
+
+	if !reflect.DeepEqual(expectedPaths, actualPaths) {
+		t.Errorf("Expected %v, got %v", expectedPaths, actualPaths)
+	}
+}
+
+func TestAllPaths6Vertices2(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("b", "c")
+	g.AddUndirected("b", "d")
+	g.AddUndirected("c", "d")
+	g.AddUndirected("c", "e")
+	g.AddUndirected("d", "e")
+	g.AddUndirected("e", "f")
+	g.AddUndirected("d", "f")
+
+	paths, err := g.AllPaths(context.Background(), "a", "f", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actualPaths := flattenAll(paths)
+
+	expectedPaths := [][]string{
+		{"a", "b", "d", "f"},
+		{"a", "b", "c", "d", "f"},
+		{"a", "b", "c", "e", "f"},
+		{"a", "b", "d", "e", "f"},
+	}
+
+	if !reflect.DeepEqual(expectedPaths, actualPaths) {
+		t.Errorf("Expected %v, got %v", expectedPaths, actualPaths)
+	}
+}
+
+func TestAllPaths8Vertices(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("b", "c")
+	g.AddUndirected("c", "e")
+	g.AddUndirected("c", "f")
+	g.AddUndirected("e", "h")
+	g.AddUndirected("f", "h")
+	g.AddUndirected("b", "d")
+	g.AddUndirected("d", "g")
+	g.AddUndirected("g", "h")
+
+	paths, err := g.AllPaths(context.Background(), "a", "h", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actualPaths := flattenAll(paths)
+
+	expectedPaths := [][]string{
+		{"a", "b", "c", "e", "h"},
+		{"a", "b", "c", "f", "h"},
+		{"a", "b", "d", "g", "h"},
+	}
+
+	if !reflect.DeepEqual(expectedPaths, actualPaths) {
+		t.Errorf("Expected %v, got %v", expectedPaths, actualPaths)
+	}
+}
+
+func TestEdgeDocuments(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddDocument("a", "b", "d-2")
+	g.AddDocument("b", "a", "d-1")
+
+	expected := []string{"d-1", "d-2"}
+
+	actual1 := g.EdgeDocuments("a", "b")
+	if !reflect.DeepEqual(expected, actual1) {
+		t.Errorf("Expected %v, got %v\n", expected, actual1)
+	}
+
+	actual2 := g.EdgeDocuments("b", "a")
+	if !reflect.DeepEqual(expected, actual2) {
+		t.Errorf("Expected %v, got %v\n", expected, actual2)
+	}
+
+	actual3 := g.EdgeDocuments("a", "c")
+	if len(actual3) != 0 {
+		t.Errorf("Expected no documents, got %v\n", actual3)
+	}
+}
+
+func TestPathDocuments(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("b", "c")
+	g.AddDocument("a", "b", "d-100")
+	g.AddDocument("a", "b", "d-200")
+	g.AddDocument("b", "c", "d-300")
+
+	actual := g.PathDocuments([]string{"a", "b", "c"})
+	expected := [][]string{{"d-100", "d-200"}, {"d-300"}}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, got %v\n", expected, actual)
+	}
+
+	formatted := formatDocuments(actual, "|")
+	if formatted != "d-100;d-200|d-300" {
+		t.Errorf("Expected d-100;d-200|d-300, got %v\n", formatted)
+	}
+}
+
+func TestWriteUndirectedEdgeListWithDocuments(t *testing.T) {
+
+	// Construct a test undirected graph with supporting documents
+	g := NewGraph()
+	g.AddUndirected("b", "a")
+	g.AddDocument("b", "a", "d-1")
+	g.AddDocument("b", "a", "d-2")
+	g.AddUndirected("c", "b")
+	g.AddDocument("c", "b", "d-3")
+
+	// Write the graph to a text file
+	actualFilepath := "./test/test-writing/actual-output-3.csv"
+	g.WriteUndirectedEdgeList(actualFilepath, ",")
+
+	// Check the result
+	if !FilesHaveSameContentIgnoringOrder(actualFilepath, "./test/test-writing/expected-output-3.csv") {
+		t.Fatalf("Actual results differ from expected results\n")
+	}
+}
+
+func TestPathCost(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("b", "c")
+	g.SetWeight("b", "c", 2.5)
+
+	actual := g.PathCost([]string{"a", "b", "c"})
+	if actual != 3.5 {
+		t.Errorf("Expected a cost of 3.5, got %v\n", actual)
+	}
+}
+
+func TestDijkstraRootNotPresent(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+
+	found, _, err := g.Dijkstra(context.Background(), "c", "a", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if found {
+		t.Errorf("Expected not to find the vertex")
+	}
+}
+
+func TestDijkstraUnitWeights(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("b", "c")
+	g.AddUndirected("c", "d")
+
+	found, vertex, err := g.Dijkstra(context.Background(), "a", "d", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !found {
+		t.Fatalf("Expected to find the vertex")
+	}
+
+	expected := []string{"a", "b", "c", "d"}
+	actual := vertex.flatten()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, got %v\n", expected, actual)
+	}
+
+	if vertex.Cost != 3 {
+		t.Errorf("Expected a cost of 3, got %v\n", vertex.Cost)
+	}
+}
+
+func TestDijkstraPrefersLowerCost(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "d")
+	g.AddUndirected("a", "b")
+	g.AddUndirected("b", "c")
+	g.AddUndirected("c", "d")
+	g.SetWeight("a", "d", 5)
+	g.SetWeight("a", "b", 0.5)
+	g.SetWeight("b", "c", 0.5)
+	g.SetWeight("c", "d", 0.5)
+
+	// BFS finds the path with the fewest hops
+	_, bfsVertex, err := g.Bfs(context.Background(), "a", "d", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual([]string{"a", "d"}, bfsVertex.flatten()) {
+		t.Errorf("Expected BFS to find a direct path, got %v\n", bfsVertex.flatten())
+	}
+
+	// Dijkstra finds the path with the lowest cost
+	found, vertex, err := g.Dijkstra(context.Background(), "a", "d", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !found {
+		t.Fatalf("Expected to find the vertex")
+	}
+
+	expected := []string{"a", "b", "c", "d"}
+	actual := vertex.flatten()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, got %v\n", expected, actual)
+	}
+
+	if vertex.Cost != 1.5 {
+		t.Errorf("Expected a cost of 1.5, got %v\n", vertex.Cost)
+	}
+
+	// The path isn't found if the maximum cost is too low
+	found, _, err = g.Dijkstra(context.Background(), "a", "d", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if found {
+		t.Errorf("Expected not to find the vertex")
+	}
+}
+
+func TestReachableWithinCost(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("b", "c")
+	g.AddUndirected("c", "d")
+	g.SetWeight("b", "c", 0.25)
+
+	found, actual, err := g.ReachableWithinCost(context.Background(), "a", 1.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !found {
+		t.Fatalf("Expected to find vertex\n")
+	}
+
+	expected := set.New("a", "b", "c")
+	if !SetsEqual(expected, actual) {
+		t.Errorf("Expected %v, found %v\n", expected, actual)
+	}
+}
+
+func TestBidirectionalBfsRootNodeNotPresent(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+
+	found, _, err := g.BidirectionalBfs(context.Background(), "c", "a", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if found {
+		t.Errorf("Expected not to find the vertex")
+	}
+}
+
+func TestBidirectionalBfsGoalNodeNotPresent(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "d")
+
+	found, _, err := g.BidirectionalBfs(context.Background(), "a", "b", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if found {
+		t.Errorf("Expected not to find the vertex")
+	}
+}
+
+func TestBidirectionalBfsRootIsGoal(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+
+	found, vertex, err := g.BidirectionalBfs(context.Background(), "a", "a", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !found {
+		t.Fatalf("Expected to find the vertex")
+	}
+
+	if !reflect.DeepEqual([]string{"a"}, vertex.flatten()) {
+		t.Errorf("Expected [a], got %v\n", vertex.flatten())
+	}
+}
+
+func TestBidirectionalBfsLine(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("b", "c")
+	g.AddUndirected("c", "d")
+	g.AddUndirected("d", "e")
+
+	found, vertex, err := g.BidirectionalBfs(context.Background(), "a", "e", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !found {
+		t.Fatalf("Expected to find the vertex")
+	}
+
+	expected := []string{"a", "b", "c", "d", "e"}
+	actual := vertex.flatten()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, got %v\n", expected, actual)
+	}
+
+	// The lineage has the correct depths
+	if vertex.Depth != 4 || vertex.Parent.Depth != 3 {
+		t.Errorf("Unexpected depths in the lineage")
+	}
+
+	// Stops searching before the goal is reached
+	found, _, err = g.BidirectionalBfs(context.Background(), "a", "e", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if found {
+		t.Errorf("Expected not to find the vertex")
+	}
+}
+
+func TestBidirectionalBfsDiamondShape(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("a", "c")
+	g.AddUndirected("b", "d")
+	g.AddUndirected("c", "d")
+	g.AddUndirected("d", "e")
+	g.AddUndirected("e", "f")
+
+	found, vertex, err := g.BidirectionalBfs(context.Background(), "a", "f", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !found {
+		t.Fatalf("Expected to find the vertex")
+	}
+
+	if len(vertex.flatten()) != 5 {
+		t.Errorf("Expected a path with 4 hops, got %v\n", vertex.flatten())
+	}
+}
+
+// readTestGraph reads a unipartite graph from the entity-document files
+func readTestGraph(t *testing.T, files []string) *Graph {
+
+	connections, err := ReadEntityDocumentGraph(files, set.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := BipartiteToUnipartite(connections, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return g
+}
+
+func TestBidirectionalBfsSameLengthAsBfs(t *testing.T) {
+
+	graphs := []*Graph{
+		readTestGraph(t, []string{
+			"./test/test-data-full/entity_doc_1.csv",
+			"./test/test-data-full/entity_doc_2.csv",
+			"./test/test-data-full/entity_doc_3.csv",
+		}),
+		readTestGraph(t, []string{
+			"./test/test-data-full-2/entity_doc_1.csv",
+			"./test/test-data-full-2/entity_doc_2.csv",
+			"./test/test-data-full-2/entity_doc_3.csv",
+		}),
+		readTestGraph(t, []string{
+			"./test/test-data-full-3/entity_doc_1.csv",
+			"./test/test-data-full-3/entity_doc_2.csv",
+		}),
+	}
+
+	for _, g := range graphs {
+		vertices := g.listOfKeys()
+
+		for _, root := range vertices {
+			for _, goal := range vertices {
+				for maxDepth := 0; maxDepth <= 5; maxDepth++ {
+
+					found1, vertex1, err := g.Bfs(context.Background(), root, goal, maxDepth)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					found2, vertex2, err := g.BidirectionalBfs(context.Background(), root, goal, maxDepth)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if found1 != found2 {
+						t.Fatalf("%v -> %v (max depth %v): BFS found %v, bidirectional found %v\n",
+							root, goal, maxDepth, found1, found2)
+					}
+
+					if !found1 {
+						continue
+					}
+
+					path1 := vertex1.flatten()
+					path2 := vertex2.flatten()
+
+					if len(path1) != len(path2) {
+						t.Fatalf("%v -> %v (max depth %v): BFS path %v, bidirectional path %v\n",
+							root, goal, maxDepth, path1, path2)
+					}
+
+					if path2[0] != root || path2[len(path2)-1] != goal {
+						t.Fatalf("Invalid path %v from %v to %v\n", path2, root, goal)
+					}
+
+					// Each hop on the path must be an edge in the graph
+					for k := 0; k < len(path2)-1; k++ {
+						if !g.Nodes[path2[k]].Has(path2[k+1]) {
+							t.Fatalf("Path %v contains a non-existent edge\n", path2)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestAllShortestPathsRootNotPresent(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+
+	paths, err := g.AllShortestPaths(context.Background(), "c", "a", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(paths) != 0 {
+		t.Errorf("Didn't expect a path, found %v paths", len(paths))
+	}
+}
+
+func TestAllShortestPaths3Vertices(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("b", "c")
+
+	// Stop too early
+	pathsStopped, err := g.AllShortestPaths(context.Background(), "a", "c", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pathsStopped) > 0 {
+		t.Errorf("Didn't expect a path, found %v paths", len(pathsStopped))
+	}
+
+	// Stop after 2 steps
+	actualPaths, err := g.AllShortestPaths(context.Background(), "a", "c", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPaths := [][]string{
+		{"a", "b", "c"},
+	}
+
+	if !reflect.DeepEqual(expectedPaths, actualPaths) {
+		t.Errorf("Expected %v, got %v", expectedPaths, actualPaths)
+	}
+}
+
+func TestAllShortestPaths6Vertices2(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("b", "c")
+	g.AddUndirected("b", "d")
+	g.AddUndirected("c", "d")
+	g.AddUndirected("c", "e")
+	g.AddUndirected("d", "e")
+	g.AddUndirected("e", "f")
+	g.AddUndirected("d", "f")
+
+	// Only the path with the fewest hops is returned (unlike AllPaths)
+	actualPaths, err := g.AllShortestPaths(context.Background(), "a", "f", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPaths := [][]string{
+		{"a", "b", "d", "f"},
+	}
+
+	if !reflect.DeepEqual(expectedPaths, actualPaths) {
+		t.Errorf("Expected %v, got %v", expectedPaths, actualPaths)
+	}
+}
+
+func TestAllShortestPathsMultiplePaths(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("a", "c")
+	g.AddUndirected("b", "d")
+	g.AddUndirected("c", "d")
+	g.AddUndirected("d", "e")
+	g.AddUndirected("d", "f")
+	g.AddUndirected("e", "g")
+	g.AddUndirected("f", "g")
+	g.AddUndirected("a", "h")
+	g.AddUndirected("h", "i")
+	g.AddUndirected("i", "j")
+	g.AddUndirected("j", "k")
+	g.AddUndirected("k", "g")
+
+	actualPaths, err := g.AllShortestPaths(context.Background(), "a", "g", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPaths := [][]string{
+		{"a", "b", "d", "e", "g"},
+		{"a", "b", "d", "f", "g"},
+		{"a", "c", "d", "e", "g"},
+		{"a", "c", "d", "f", "g"},
+	}
+
+	if !reflect.DeepEqual(expectedPaths, actualPaths) {
+		t.Errorf("Expected %v, got %v", expectedPaths, actualPaths)
+	}
+}
+
+func TestKShortestPathsNotFound(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("c", "d")
+
+	paths, err := g.KShortestPaths(context.Background(), "a", "d", 3, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(paths) != 0 {
+		t.Errorf("Didn't expect a path, found %v paths", len(paths))
+	}
+}
+
+func TestKShortestPaths6Vertices2(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("b", "c")
+	g.AddUndirected("b", "d")
+	g.AddUndirected("c", "d")
+	g.AddUndirected("c", "e")
+	g.AddUndirected("d", "e")
+	g.AddUndirected("e", "f")
+	g.AddUndirected("d", "f")
+
+	// First path only
+	actualPaths, err := g.KShortestPaths(context.Background(), "a", "f", 1, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPaths := [][]string{
+		{"a", "b", "d", "f"},
+	}
+
+	if !reflect.DeepEqual(expectedPaths, actualPaths) {
+		t.Errorf("Expected %v, got %v", expectedPaths, actualPaths)
+	}
+
+	// First three paths
+	actualPaths, err = g.KShortestPaths(context.Background(), "a", "f", 3, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPaths = [][]string{
+		{"a", "b", "d", "f"},
+		{"a", "b", "c", "d", "f"},
+		{"a", "b", "c", "e", "f"},
+	}
+
+	if !reflect.DeepEqual(expectedPaths, actualPaths) {
+		t.Errorf("Expected %v, got %v", expectedPaths, actualPaths)
+	}
+
+	// More paths requested than exist gives the same paths as AllPaths
+	actualPaths, err = g.KShortestPaths(context.Background(), "a", "f", 10, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nodes, err := g.AllPaths(context.Background(), "a", "f", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedPaths = flattenAll(nodes)
+
+	if !reflect.DeepEqual(expectedPaths, actualPaths) {
+		t.Errorf("Expected %v, got %v", expectedPaths, actualPaths)
+	}
+}
+
+func TestKShortestPathsMaxDepth(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("b", "c")
+	g.AddUndirected("a", "d")
+	g.AddUndirected("d", "e")
+	g.AddUndirected("e", "c")
+
+	// The longer path is beyond the maximum depth
+	actualPaths, err := g.KShortestPaths(context.Background(), "a", "c", 5, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPaths := [][]string{
+		{"a", "b", "c"},
+	}
+
+	if !reflect.DeepEqual(expectedPaths, actualPaths) {
+		t.Errorf("Expected %v, got %v", expectedPaths, actualPaths)
+	}
+
+	actualPaths, err = g.KShortestPaths(context.Background(), "a", "c", 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPaths = [][]string{
+		{"a", "b", "c"},
+		{"a", "d", "e", "c"},
+	}
+
+	if !reflect.DeepEqual(expectedPaths, actualPaths) {
+		t.Errorf("Expected %v, got %v", expectedPaths, actualPaths)
+	}
+}
+
+func TestGraphInvalidEdges(t *testing.T) {
+	g := NewGraph()
+
+	testCases := []struct {
+		source      string
+		destination string
+		expected    error
+	}{
+		{"", "b", ErrEmptyVertex},
+		{"a", "", ErrEmptyVertex},
+		{"a", "a", ErrSelfLoop},
+	}
+
+	for _, testCase := range testCases {
+		if err := g.AddDirected(testCase.source, testCase.destination); !errors.Is(err, testCase.expected) {
+			t.Errorf("%v -> %v: expected %v, got %v\n", testCase.source, testCase.destination, testCase.expected, err)
+		}
+
+		if err := g.AddUndirected(testCase.source, testCase.destination); !errors.Is(err, testCase.expected) {
+			t.Errorf("%v -- %v: expected %v, got %v\n", testCase.source, testCase.destination, testCase.expected, err)
+		}
+	}
+
+	if len(g.Nodes) != 0 {
+		t.Fatalf("Expected an empty graph, got %v vertices\n", len(g.Nodes))
+	}
+
+	if err := g.AddDocument("a", "b", ""); !errors.Is(err, ErrEmptyDocument) {
+		t.Errorf("Expected %v, got %v\n", ErrEmptyDocument, err)
+	}
+
+	if err := g.SetWeight("a", "b", -1); !errors.Is(err, ErrNegativeWeight) {
+		t.Errorf("Expected %v, got %v\n", ErrNegativeWeight, err)
+	}
+}
+
+func TestNewVertexInvalid(t *testing.T) {
+	if _, err := NewVertex("", 0); !errors.Is(err, ErrEmptyVertex) {
+		t.Errorf("Expected %v, got %v\n", ErrEmptyVertex, err)
+	}
+
+	if _, err := NewVertex("a", -1); !errors.Is(err, ErrInvalidDepth) {
+		t.Errorf("Expected %v, got %v\n", ErrInvalidDepth, err)
+	}
+}
+
+func TestSearchInvalidArguments(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+
+	testCases := []struct {
+		root     string
+		goal     string
+		maxDepth int
+		expected error
+	}{
+		{"", "b", 1, ErrEmptyVertex},
+		{"a", "", 1, ErrEmptyVertex},
+		{"a", "b", -1, ErrInvalidDepth},
+	}
+
+	for _, testCase := range testCases {
+		if _, _, err := g.Bfs(context.Background(), testCase.root, testCase.goal, testCase.maxDepth); !errors.Is(err, testCase.expected) {
+			t.Errorf("Bfs(%v, %v, %v): expected %v, got %v\n", testCase.root, testCase.goal, testCase.maxDepth, testCase.expected, err)
+		}
+
+		if _, _, err := g.BidirectionalBfs(context.Background(), testCase.root, testCase.goal, testCase.maxDepth); !errors.Is(err, testCase.expected) {
+			t.Errorf("BidirectionalBfs(%v, %v, %v): expected %v, got %v\n", testCase.root, testCase.goal, testCase.maxDepth, testCase.expected, err)
+		}
+
+		if _, err := g.AllPaths(context.Background(), testCase.root, testCase.goal, testCase.maxDepth); !errors.Is(err, testCase.expected) {
+			t.Errorf("AllPaths(%v, %v, %v): expected %v, got %v\n", testCase.root, testCase.goal, testCase.maxDepth, testCase.expected, err)
+		}
+	}
+
+	if _, _, err := g.Dijkstra(context.Background(), "a", "b", -1); !errors.Is(err, ErrInvalidCost) {
+		t.Errorf("Expected %v, got %v\n", ErrInvalidCost, err)
+	}
+
+	if _, err := g.KShortestPaths(context.Background(), "a", "b", 0, 1); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("Expected %v, got %v\n", ErrInvalidArgument, err)
+	}
+}
+
+func TestSearchCancelled(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("b", "c")
+	c := g.Freeze()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, _, err := g.Bfs(ctx, "a", "c", 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("Bfs: expected %v, got %v\n", context.Canceled, err)
+	}
+
+	if _, _, err := c.Bfs(ctx, "a", "c", 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("Compact Bfs: expected %v, got %v\n", context.Canceled, err)
+	}
+
+	if _, err := g.AllPaths(ctx, "a", "c", 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("AllPaths: expected %v, got %v\n", context.Canceled, err)
+	}
+
+	if _, err := c.AllPaths(ctx, "a", "c", 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("Compact AllPaths: expected %v, got %v\n", context.Canceled, err)
+	}
+
+	if _, _, err := g.ReachableVertices(ctx, "a", 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("ReachableVertices: expected %v, got %v\n", context.Canceled, err)
+	}
+
+	if _, _, err := c.ReachableVertices(ctx, "a", 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("Compact ReachableVertices: expected %v, got %v\n", context.Canceled, err)
+	}
+
+	if _, err := g.AllShortestPaths(ctx, "a", "c", 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("AllShortestPaths: expected %v, got %v\n", context.Canceled, err)
+	}
+
+	if _, _, err := g.Dijkstra(ctx, "a", "c", 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("Dijkstra: expected %v, got %v\n", context.Canceled, err)
+	}
+
+	if _, _, err := g.ReachableWithinCost(ctx, "a", 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("ReachableWithinCost: expected %v, got %v\n", context.Canceled, err)
+	}
+
+	if _, _, err := g.BidirectionalBfs(ctx, "a", "c", 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("BidirectionalBfs: expected %v, got %v\n", context.Canceled, err)
+	}
+
+	if _, err := g.bfsAvoiding(ctx, "a", "c", 2, set.New(), set.New()); !errors.Is(err, context.Canceled) {
+		t.Errorf("bfsAvoiding: expected %v, got %v\n", context.Canceled, err)
+	}
+
+	if _, _, err := c.Neighbourhood(ctx, "a", 0, 2, 0, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Compact Neighbourhood: expected %v, got %v\n", context.Canceled, err)
+	}
+
+	if _, err := g.KShortestPaths(ctx, "a", "c", 2, 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("KShortestPaths: expected %v, got %v\n", context.Canceled, err)
+	}
+}
@@ -0,0 +1,210 @@
+package spbfs
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-collections/collections/set"
+	"github.com/klauspost/compress/zstd"
+)
+
+// StdinPath is the path of an input file that is read from standard input
+const StdinPath = "-"
+
+// stdin is the reader used for the StdinPath (replaced in the tests)
+var stdin io.Reader = os.Stdin
+
+// Magic bytes at the start of compressed data
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// Compression formats of input files
+const (
+	compressionNone = ""
+	compressionGzip = "gzip"
+	compressionZstd = "zstd"
+)
+
+// detectCompression returns the compression format of data given the first bytes, falling back
+// on the file extension
+func detectCompression(path string, header []byte) string {
+
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		return compressionGzip
+	case bytes.HasPrefix(header, zstdMagic):
+		return compressionZstd
+	case strings.HasSuffix(path, ".gz"):
+		return compressionGzip
+	case strings.HasSuffix(path, ".zst"):
+		return compressionZstd
+	}
+
+	return compressionNone
+}
+
+// inputReader reads the (decompressed) data of an input and closes the underlying readers
+type inputReader struct {
+	io.Reader
+	closers []io.Closer // readers to close, with the outermost first
+}
+
+// Close closes the readers, returning the first error
+func (r *inputReader) Close() error {
+
+	var err error
+	for _, closer := range r.closers {
+		if closeErr := closer.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
+
+// openInput opens a file for reading, or standard input if the path is the StdinPath. Data
+// compressed using gzip or zstd is decompressed while it's read.
+func openInput(path string) (io.ReadCloser, error) {
+
+	reader := &inputReader{}
+
+	if path == StdinPath {
+		reader.Reader = stdin
+	} else {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+
+		reader.Reader = file
+		reader.closers = append(reader.closers, file)
+	}
+
+	// Peek at the start of the data to detect the compression (a short input isn't an error)
+	buffered := bufio.NewReader(reader.Reader)
+	header, err := buffered.Peek(len(zstdMagic))
+	if err != nil && err != io.EOF {
+		reader.Close()
+		return nil, err
+	}
+	reader.Reader = buffered
+
+	switch detectCompression(path, header) {
+
+	case compressionGzip:
+		decompressor, err := gzip.NewReader(buffered)
+		if err != nil {
+			reader.Close()
+			return nil, fmt.Errorf("unable to read gzip data: %w", err)
+		}
+		reader.Reader = decompressor
+		reader.closers = append([]io.Closer{decompressor}, reader.closers...)
+
+	case compressionZstd:
+		decoder, err := zstd.NewReader(buffered)
+		if err != nil {
+			reader.Close()
+			return nil, fmt.Errorf("unable to read zstd data: %w", err)
+		}
+		decompressor := decoder.IOReadCloser()
+		reader.Reader = decompressor
+		reader.closers = append([]io.Closer{decompressor}, reader.closers...)
+	}
+
+	return reader, nil
+}
+
+// hasGlobMeta returns true if the path contains any of the special characters of a glob pattern
+func hasGlobMeta(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// expandInputPath returns the files for a path, which may be a glob pattern or a directory. The
+// files matched by a pattern and the files in a directory (excluding subdirectories and hidden
+// files) are in sorted order. A path that exists isn't treated as a pattern, so a file found in a
+// directory, such as data[1].csv, is still found when the files are expanded again.
+func expandInputPath(path string) ([]string, error) {
+
+	if path == StdinPath {
+		return []string{path}, nil
+	}
+
+	matches := []string{path}
+	if _, err := os.Stat(path); err != nil && hasGlobMeta(path) {
+		var err error
+		if matches, err = filepath.Glob(path); err != nil {
+			return nil, fmt.Errorf("invalid pattern %v: %v", path, err)
+		}
+		sort.Strings(matches)
+	}
+
+	var files []string
+	for _, match := range matches {
+
+		// A file that doesn't exist is reported when it's read
+		info, err := os.Stat(match)
+		if err != nil || !info.IsDir() {
+			files = append(files, match)
+			continue
+		}
+
+		// The entries of a directory are sorted by name
+		entries, err := os.ReadDir(match)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read directory %v: %v", match, err)
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+				files = append(files, filepath.Join(match, entry.Name()))
+			}
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files found for %v", path)
+	}
+
+	return files, nil
+}
+
+// expandInputFiles expands the input files whose paths are glob patterns or directories into an
+// input file for each file found, with the same weight and layout. A file found by more than one
+// entry is only read once.
+func expandInputFiles(files []InputFile) ([]InputFile, error) {
+
+	var expanded []InputFile
+	found := set.New()
+
+	for _, file := range files {
+
+		paths, err := expandInputPath(file.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, path := range paths {
+			if found.Has(filepath.Clean(path)) {
+				log.Printf("Input file %v is given more than once, so it will only be read once\n", path)
+				continue
+			}
+			found.Insert(filepath.Clean(path))
+
+			expandedFile := file
+			expandedFile.Path = path
+			expanded = append(expanded, expandedFile)
+		}
+	}
+
+	return expanded, nil
+}
@@ -0,0 +1,247 @@
+package spbfs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/golang-collections/collections/set"
+	"github.com/klauspost/compress/zstd"
+)
+
+// compressGzip returns the data compressed using gzip
+func compressGzip(t *testing.T, data []byte) []byte {
+
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+
+	if _, err := writer.Write(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
+
+// compressZstd returns the data compressed using zstd
+func compressZstd(t *testing.T, data []byte) []byte {
+
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer encoder.Close()
+
+	return encoder.EncodeAll(data, nil)
+}
+
+func TestReadEntityDocumentGraphFromCompressedFile(t *testing.T) {
+	data, err := os.ReadFile("./test/test-data/entity_1.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := ReadEntityDocumentGraphFromFile("./test/test-data/entity_1.csv", set.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	testCases := []struct {
+		name string
+		data []byte
+	}{
+		{"entity_1.csv.gz", compressGzip(t, data)},
+		{"entity_1.csv.zst", compressZstd(t, data)},
+		{"entity_1_gzip.csv", compressGzip(t, data)}, // detected from the magic bytes
+		{"entity_1_zstd.csv", compressZstd(t, data)},
+	}
+
+	for _, testCase := range testCases {
+		path := filepath.Join(dir, testCase.name)
+		if err := os.WriteFile(path, testCase.data, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := ReadEntityDocumentGraphFromFile(path, set.New())
+		if err != nil {
+			t.Fatalf("%v: %v\n", testCase.name, err)
+		}
+
+		if !reflect.DeepEqual(expected, result) {
+			t.Errorf("%v: expected %v, got %v\n", testCase.name, expected, result)
+		}
+	}
+}
+
+func TestReadEntityDocumentGraphFromStdin(t *testing.T) {
+	data, err := os.ReadFile("./test/test-data/entity_1.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := ReadEntityDocumentGraphFromFile("./test/test-data/entity_1.csv", set.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(original io.Reader) { stdin = original }(stdin)
+
+	for _, input := range [][]byte{data, compressGzip(t, data)} {
+		stdin = bytes.NewReader(input)
+
+		result, err := ReadEntityDocumentGraphFromFile(StdinPath, set.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(expected, result) {
+			t.Errorf("Expected %v, got %v\n", expected, result)
+		}
+	}
+}
+
+func TestReadEntityDocumentGraphFromInvalidCompressedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entity_1.csv.gz")
+	if err := os.WriteFile(path, []byte("entity_id,document_id\ne-1,d-1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ReadEntityDocumentGraphFromFile(path, set.New()); err == nil {
+		t.Error("Expected an error reading a file that isn't compressed using gzip")
+	}
+}
+
+// writeInputTestFiles creates empty files in a directory, creating the subdirectories as required
+func writeInputTestFiles(t *testing.T, dir string, names []string) {
+
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte("entity_id,document_id\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// writeInputTestConfig writes a config with the input files to a directory
+func writeInputTestConfig(t *testing.T, dir string, inputFiles []interface{}) string {
+
+	config := map[string]interface{}{
+		"input_files": inputFiles,
+		"output": map[string]interface{}{
+			"max_depth":      3,
+			"delimiter":      ",",
+			"path_delimiter": "|",
+		},
+	}
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestReadConfigExpandsInputFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeInputTestFiles(t, dir, []string{
+		"2024-02/entity_doc_1.csv",
+		"2024-01/entity_doc_2.csv",
+		"2024-01/entity_doc_1.csv",
+		"2024-01/notes.txt",
+		"2023-12/entity_doc_1.csv",
+		"daily/b.csv",
+		"daily/a.csv",
+		"daily/.hidden.csv",
+		"daily/archive/c.csv",
+	})
+
+	configFile := writeInputTestConfig(t, dir, []interface{}{
+		filepath.Join(dir, "2024-*", "entity_doc_*.csv"),
+		map[string]interface{}{"path": filepath.Join(dir, "daily"), "weight": 2},
+		filepath.Join(dir, "2024-01", "entity_doc_1.csv"),
+		StdinPath,
+	})
+
+	config, err := ReadConfig(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []InputFile{
+		{Path: filepath.Join(dir, "2024-01", "entity_doc_1.csv"), Weight: 1.0},
+		{Path: filepath.Join(dir, "2024-01", "entity_doc_2.csv"), Weight: 1.0},
+		{Path: filepath.Join(dir, "2024-02", "entity_doc_1.csv"), Weight: 1.0},
+		{Path: filepath.Join(dir, "daily", "a.csv"), Weight: 2.0},
+		{Path: filepath.Join(dir, "daily", "b.csv"), Weight: 2.0},
+		{Path: StdinPath, Weight: 1.0},
+	}
+
+	if !reflect.DeepEqual(expected, config.InputFiles) {
+		t.Fatalf("Expected %v, got %v\n", expected, config.InputFiles)
+	}
+
+	// The files are recorded in the parameters that are displayed
+	params := config.parameters()
+	for i, file := range expected {
+		if params[i+1].Value != file.Path {
+			t.Errorf("Expected parameter %v to be %v, got %v\n", params[i+1].Name, file.Path, params[i+1].Value)
+		}
+	}
+}
+
+func TestNormalizeTwice(t *testing.T) {
+	dir := t.TempDir()
+	writeInputTestFiles(t, dir, []string{"data/data[1].csv"})
+
+	config, err := ReadConfig(writeInputTestConfig(t, dir, []interface{}{filepath.Join(dir, "data")}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The file found in the directory isn't treated as a pattern when the config is normalized again
+	if err := config.Normalize(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []InputFile{{Path: filepath.Join(dir, "data", "data[1].csv"), Weight: 1.0}}
+	if !reflect.DeepEqual(expected, config.InputFiles) {
+		t.Errorf("Expected %v, got %v\n", expected, config.InputFiles)
+	}
+}
+
+func TestReadConfigExpandsInputFilesInvalid(t *testing.T) {
+	dir := t.TempDir()
+	writeInputTestFiles(t, dir, []string{"data/.hidden.csv"})
+
+	for _, pattern := range []string{
+		filepath.Join(dir, "2024-*", "entity_doc_*.csv"), // no matches
+		filepath.Join(dir, "data"),                       // no visible files
+		filepath.Join(dir, "[-"),                         // invalid pattern
+	} {
+		configFile := writeInputTestConfig(t, dir, []interface{}{pattern})
+
+		if _, err := ReadConfig(configFile); !errors.Is(err, ErrConfig) {
+			t.Errorf("%v: expected %v, got %v\n", pattern, ErrConfig, err)
+		}
+	}
+}
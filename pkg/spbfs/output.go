@@ -0,0 +1,192 @@
+package spbfs
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+)
+
+// Formats for the file of path results
+const (
+	OutputFormatCSV   = "csv"   // delimited file with a header row
+	OutputFormatJSONL = "jsonl" // one JSON object per path, followed by a summary object
+	OutputFormatJSON  = "json"  // a single JSON object with the list of paths and the summary
+)
+
+// outputFormat returns the format of the output file, which defaults to CSV
+func (c *OutputConfig) outputFormat() string {
+
+	if len(c.OutputFormat) > 0 {
+		return c.OutputFormat
+	}
+
+	return OutputFormatCSV
+}
+
+// resultWriter writes the path results to the output file in the required format
+type resultWriter interface {
+	begin() error                  // write anything required before the results
+	resume(numResults int) error   // continue after the results already in the file (instead of begin)
+	write(result PathResult) error // write a single path result
+	flush() error                  // make the results written so far available in the file
+	end(summary Summary) error     // write anything required after the results and flush
+}
+
+// newResultWriter returns the writer for the output format in the config
+func newResultWriter(w io.Writer, outputConfig OutputConfig) (resultWriter, error) {
+
+	switch outputConfig.outputFormat() {
+	case OutputFormatJSONL:
+		return &jsonlResultWriter{writer: bufio.NewWriter(w)}, nil
+	case OutputFormatJSON:
+		return &jsonResultWriter{writer: bufio.NewWriter(w)}, nil
+	}
+
+	writer, err := NewCSVWriter(w, outputConfig.OutputDelimiter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &csvResultWriter{
+		writer:        writer,
+		pathDelimiter: outputConfig.PathDelimiter,
+	}, nil
+}
+
+// csvResultWriter writes the path results as rows of a delimited file
+type csvResultWriter struct {
+	writer        *csv.Writer
+	pathDelimiter string
+}
+
+func (c *csvResultWriter) begin() error {
+	return c.writer.Write(pathResultHeaderRecord())
+}
+
+func (c *csvResultWriter) resume(numResults int) error {
+	return nil
+}
+
+func (c *csvResultWriter) write(result PathResult) error {
+
+	record, err := result.toRecord(c.pathDelimiter)
+	if err != nil {
+		return err
+	}
+
+	return c.writer.Write(record)
+}
+
+func (c *csvResultWriter) flush() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+func (c *csvResultWriter) end(summary Summary) error {
+	return c.flush()
+}
+
+// jsonlSummary is the trailer of a JSON Lines file
+type jsonlSummary struct {
+	Summary Summary `json:"summary"`
+}
+
+// jsonlResultWriter writes each path result as a JSON object on its own line, followed by a
+// line with the summary
+type jsonlResultWriter struct {
+	writer *bufio.Writer
+}
+
+func (j *jsonlResultWriter) begin() error {
+	return nil
+}
+
+func (j *jsonlResultWriter) resume(numResults int) error {
+	return nil
+}
+
+func (j *jsonlResultWriter) write(result PathResult) error {
+	return json.NewEncoder(j.writer).Encode(result)
+}
+
+func (j *jsonlResultWriter) flush() error {
+	return j.writer.Flush()
+}
+
+func (j *jsonlResultWriter) end(summary Summary) error {
+
+	if err := json.NewEncoder(j.writer).Encode(jsonlSummary{Summary: summary}); err != nil {
+		return err
+	}
+
+	return j.flush()
+}
+
+// jsonResultWriter writes a single JSON object with the list of path results and the summary.
+// The results are written as they are found, so they don't need to be held in memory.
+type jsonResultWriter struct {
+	writer     *bufio.Writer
+	numResults int
+}
+
+func (j *jsonResultWriter) begin() error {
+	_, err := j.writer.WriteString("{\"results\":[")
+	return err
+}
+
+func (j *jsonResultWriter) resume(numResults int) error {
+	j.numResults = numResults
+	return nil
+}
+
+func (j *jsonResultWriter) write(result PathResult) error {
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	if j.numResults > 0 {
+		if err := j.writer.WriteByte(','); err != nil {
+			return err
+		}
+	}
+
+	if _, err := j.writer.WriteString("\n"); err != nil {
+		return err
+	}
+
+	if _, err := j.writer.Write(encoded); err != nil {
+		return err
+	}
+
+	j.numResults++
+	return nil
+}
+
+func (j *jsonResultWriter) flush() error {
+	return j.writer.Flush()
+}
+
+func (j *jsonResultWriter) end(summary Summary) error {
+
+	encoded, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+
+	if _, err := j.writer.WriteString("\n],\"summary\":"); err != nil {
+		return err
+	}
+
+	if _, err := j.writer.Write(encoded); err != nil {
+		return err
+	}
+
+	if _, err := j.writer.WriteString("}\n"); err != nil {
+		return err
+	}
+
+	return j.flush()
+}
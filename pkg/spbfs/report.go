@@ -0,0 +1,336 @@
+package spbfs
+
+import (
+	"bufio"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// reportFilePath returns the location of the HTML report, next to the output file
+func reportFilePath(outputFile string) string {
+
+	ext := filepath.Ext(outputFile)
+	if strings.ToLower(ext) == ".html" {
+		return strings.TrimSuffix(outputFile, ext) + "-report.html"
+	}
+
+	return strings.TrimSuffix(outputFile, ext) + ".html"
+}
+
+// reportBreakdown summarises the paths found between a pair of data sources
+type reportBreakdown struct {
+	SourceDataSource      string  // data source of the source entities
+	DestinationDataSource string  // data source of the destination entities
+	PairsWithPaths        int     // number of entity pairs connected by a path
+	PathsFound            int     // number of paths found
+	MinHops               int     // fewest hops on a path
+	MaxHops               int     // most hops on a path
+	MeanHops              float64 // mean number of hops on a path
+}
+
+// reportHistogramBar is the number of paths with a given number of hops
+type reportHistogramBar struct {
+	Hops    int     // number of hops
+	Count   int     // number of paths with the number of hops
+	Percent float64 // percentage of the longest bar
+}
+
+// maxReportResults is the maximum number of paths listed in the HTML report, so that a large run
+// doesn't keep every path in memory. The breakdown and histogram cover all of the paths.
+const maxReportResults = 10000
+
+// htmlReport holds the contents of the HTML report
+type htmlReport struct {
+	Generated  string               // time the report was generated
+	Parameters []parameter          // run configuration
+	Summary    Summary              // summary statistics
+	Percentage string               // percentage of pairs with paths
+	Results    []PathResult         // paths listed (up to the maximum number)
+	NumResults int                  // number of paths found, including those that aren't listed
+	Breakdown  []reportBreakdown    // paths found for each pair of data sources
+	Histogram  []reportHistogramBar // paths found by number of hops
+	PathDelim  string               // delimiter between entity IDs on a path
+}
+
+// reportPair is a pair of entities with paths in the breakdown of a pair of data sources
+type reportPair struct {
+	breakdown   int    // index of the pair of data sources in the breakdown
+	source      string // source entity ID
+	destination string // destination entity ID (blank in neighbourhood mode)
+}
+
+// reportBuilder gathers the statistics for the report as each path is found, keeping only the
+// paths that are listed
+type reportBuilder struct {
+	maxResults    int                 // maximum number of paths to list
+	neighbourhood bool                // are the paths from seed entities to their neighbourhoods?
+	results       []PathResult        // paths listed
+	numResults    int                 // number of paths found
+	breakdown     []reportBreakdown   // paths found for each pair of data sources, in order of first appearance
+	index         map[[2]string]int   // index of each pair of data sources in the breakdown
+	pairs         map[reportPair]bool // pairs of entities with paths
+	totalHops     []int               // total number of hops for each pair of data sources
+	hops          map[int]int         // number of paths with each number of hops
+}
+
+// newReportBuilder returns a builder that lists up to maxResults paths. In neighbourhood mode, a
+// seed entity and its neighbourhood count as one pair, as they do in the summary.
+func newReportBuilder(maxResults int, neighbourhood bool) *reportBuilder {
+	return &reportBuilder{
+		maxResults:    maxResults,
+		neighbourhood: neighbourhood,
+		index:         make(map[[2]string]int),
+		pairs:         make(map[reportPair]bool),
+		hops:          make(map[int]int),
+	}
+}
+
+// add records a path found
+func (b *reportBuilder) add(result PathResult) {
+
+	b.numResults++
+	if len(b.results) < b.maxResults {
+		b.results = append(b.results, result)
+	}
+
+	// Breakdown by pair of data sources
+	key := [2]string{result.SourceEntityDataSource, result.DestinationEntityDataSource}
+
+	i, ok := b.index[key]
+	if !ok {
+		i = len(b.breakdown)
+		b.index[key] = i
+		b.breakdown = append(b.breakdown, reportBreakdown{
+			SourceDataSource:      key[0],
+			DestinationDataSource: key[1],
+			MinHops:               result.NumberOfHops,
+			MaxHops:               result.NumberOfHops,
+		})
+		b.totalHops = append(b.totalHops, 0)
+	}
+
+	breakdown := &b.breakdown[i]
+	breakdown.PathsFound++
+
+	// A pair of entities can have more than one path (and every path in a neighbourhood has rank 1)
+	pair := reportPair{breakdown: i, source: result.SourceEntityID, destination: result.DestinationEntityID}
+	if b.neighbourhood {
+		pair.destination = ""
+	}
+
+	if !b.pairs[pair] {
+		b.pairs[pair] = true
+		breakdown.PairsWithPaths++
+	}
+
+	if result.NumberOfHops < breakdown.MinHops {
+		breakdown.MinHops = result.NumberOfHops
+	}
+
+	if result.NumberOfHops > breakdown.MaxHops {
+		breakdown.MaxHops = result.NumberOfHops
+	}
+
+	b.totalHops[i] += result.NumberOfHops
+	b.hops[result.NumberOfHops]++
+}
+
+// build builds the contents of the report from the paths found
+func (b *reportBuilder) build(config PathConfig, summary Summary) htmlReport {
+
+	report := htmlReport{
+		Generated:  time.Now().Format(time.RFC1123),
+		Parameters: config.parameters(),
+		Summary:    summary,
+		Percentage: "0.00",
+		Results:    b.results,
+		NumResults: b.numResults,
+		Breakdown:  b.breakdown,
+		PathDelim:  config.Output.PathDelimiter,
+	}
+
+	if summary.TotalPairs > 0 {
+		report.Percentage = fmt.Sprintf("%.2f", 100.0*float64(summary.PairsWithPaths)/float64(summary.TotalPairs))
+	}
+
+	if len(report.PathDelim) == 0 {
+		report.PathDelim = " "
+	}
+
+	for i := range report.Breakdown {
+		report.Breakdown[i].MeanHops = float64(b.totalHops[i]) / float64(report.Breakdown[i].PathsFound)
+	}
+
+	// Histogram of the number of hops
+	maxCount := 0
+	for h, count := range b.hops {
+		report.Histogram = append(report.Histogram, reportHistogramBar{Hops: h, Count: count})
+		if count > maxCount {
+			maxCount = count
+		}
+	}
+
+	sort.Slice(report.Histogram, func(i, j int) bool {
+		return report.Histogram[i].Hops < report.Histogram[j].Hops
+	})
+
+	for i := range report.Histogram {
+		report.Histogram[i].Percent = 100.0 * float64(report.Histogram[i].Count) / float64(maxCount)
+	}
+
+	return report
+}
+
+// reportTemplate is the self-contained HTML report
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"join": strings.Join,
+	"documents": func(docs [][]string, delimiter string) string {
+		return formatDocuments(docs, delimiter)
+	},
+	"percent": func(p float64) string {
+		return fmt.Sprintf("%.1f%%", p)
+	},
+	"mean": func(m float64) string {
+		return fmt.Sprintf("%.2f", m)
+	},
+	"cost": formatWeight,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Shortest path report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+h1, h2 { font-weight: normal; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; }
+th { background: #f0f0f0; }
+table.sortable th { cursor: pointer; }
+table.sortable th:after { content: " \2195"; color: #999; }
+td.number { text-align: right; }
+.bar { background: #4a7ebb; height: 1em; }
+.histogram td { border: none; }
+</style>
+</head>
+<body>
+<h1>Shortest path report</h1>
+<p>Generated {{.Generated}}</p>
+
+<h2>Summary</h2>
+<table>
+<tr><th>Total number of entity pairs</th><td class="number">{{.Summary.TotalPairs}}</td></tr>
+<tr><th>Number of pairs with paths</th><td class="number">{{.Summary.PairsWithPaths}}</td></tr>
+<tr><th>Percentage of pairs with paths</th><td class="number">{{.Percentage}} %</td></tr>
+<tr><th>Total number of paths found</th><td class="number">{{.Summary.PathsFound}}</td></tr>
+{{- if .Summary.PairsTimedOut}}
+<tr><th>Number of pairs timed out</th><td class="number">{{.Summary.PairsTimedOut}}</td></tr>
+{{- end}}
+</table>
+
+<h2>Paths by data source</h2>
+<table class="sortable">
+<thead><tr><th>Source data source</th><th>Destination data source</th><th>Pairs with paths</th><th>Paths found</th><th>Min hops</th><th>Mean hops</th><th>Max hops</th></tr></thead>
+<tbody>
+{{- range .Breakdown}}
+<tr><td>{{.SourceDataSource}}</td><td>{{.DestinationDataSource}}</td><td class="number">{{.PairsWithPaths}}</td><td class="number">{{.PathsFound}}</td><td class="number">{{.MinHops}}</td><td class="number">{{mean .MeanHops}}</td><td class="number">{{.MaxHops}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+
+<h2>Paths by number of hops</h2>
+<table class="histogram">
+{{- range .Histogram}}
+<tr><td>{{.Hops}}</td><td style="width: 30em"><div class="bar" style="width: {{percent .Percent}}"></div></td><td class="number">{{.Count}}</td></tr>
+{{- end}}
+</table>
+
+<h2>Paths</h2>
+{{- if lt (len .Results) .NumResults}}
+<p>Only the first {{len .Results}} of the {{.NumResults}} paths are listed. All of the paths are in the output file.</p>
+{{- end}}
+<table class="sortable">
+<thead><tr><th>Source entity ID</th><th>Source data source</th><th>Destination entity ID</th><th>Destination data source</th><th>Number of hops</th><th>Path cost</th><th>Path</th><th>Documents</th><th>Path mode</th><th>Rank</th></tr></thead>
+<tbody>
+{{- $delim := .PathDelim}}
+{{- range .Results}}
+<tr><td>{{.SourceEntityID}}</td><td>{{.SourceEntityDataSource}}</td><td>{{.DestinationEntityID}}</td><td>{{.DestinationEntityDataSource}}</td><td class="number">{{.NumberOfHops}}</td><td class="number">{{cost .Cost}}</td><td>{{if .WebAppLink}}<a href="{{.WebAppLink}}">{{join .Path $delim}}</a>{{else}}{{join .Path $delim}}{{end}}</td><td>{{documents .Documents $delim}}</td><td>{{.Mode}}</td><td class="number">{{.Rank}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+
+<h2>Configuration</h2>
+<table>
+{{- range .Parameters}}
+<tr><th>{{.Name}}</th><td>{{.Value}}</td></tr>
+{{- end}}
+</table>
+
+<script>
+document.querySelectorAll("table.sortable").forEach(function (table) {
+  table.querySelectorAll("th").forEach(function (th, column) {
+    var ascending = true;
+    th.addEventListener("click", function () {
+      var body = table.tBodies[0];
+      var rows = Array.prototype.slice.call(body.rows);
+      rows.sort(function (a, b) {
+        var x = a.cells[column].textContent, y = b.cells[column].textContent;
+        var nx = parseFloat(x), ny = parseFloat(y);
+        var c = (!isNaN(nx) && !isNaN(ny)) ? nx - ny : x.localeCompare(y);
+        return ascending ? c : -c;
+      });
+      ascending = !ascending;
+      rows.forEach(function (row) { body.appendChild(row); });
+    });
+  });
+});
+</script>
+</body>
+</html>
+`))
+
+// writeHTMLReport writes the report to a writer
+func writeHTMLReport(w io.Writer, report htmlReport) error {
+
+	b := bufio.NewWriter(w)
+
+	if err := reportTemplate.Execute(b, report); err != nil {
+		return err
+	}
+
+	return b.Flush()
+}
+
+// writeHTMLReportFile writes the report to a file
+func writeHTMLReportFile(filePath string, report htmlReport) error {
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("unable to open report file %v for writing: %w", filePath, err)
+	}
+	defer file.Close()
+
+	if err := writeHTMLReport(file, report); err != nil {
+		return fmt.Errorf("unable to write report file %v: %w", filePath, err)
+	}
+
+	return nil
+}
+
+// reportWriter adds each path result to the report before passing it to the next writer
+type reportWriter struct {
+	resultWriter
+	*reportBuilder
+}
+
+func (r *reportWriter) write(result PathResult) error {
+	if !result.timedOut() {
+		r.add(result)
+	}
+	return r.resultWriter.write(result)
+}
@@ -0,0 +1,163 @@
+package spbfs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReportFilePath(t *testing.T) {
+	testCases := []struct {
+		outputFile string
+		expected   string
+	}{
+		{"results.csv", "results.html"},
+		{"./out/results.jsonl", "./out/results.html"},
+		{"results", "results.html"},
+		{"results.html", "results-report.html"},
+	}
+
+	for _, testCase := range testCases {
+		if actual := reportFilePath(testCase.outputFile); actual != testCase.expected {
+			t.Errorf("%v: expected %v, got %v\n", testCase.outputFile, testCase.expected, actual)
+		}
+	}
+}
+
+// buildTestReport builds the report for the path results, listing up to maxResults paths
+func buildTestReport(results []PathResult, summary Summary, maxResults int) htmlReport {
+
+	builder := newReportBuilder(maxResults, false)
+	for _, result := range results {
+		builder.add(result)
+	}
+
+	return builder.build(PathConfig{}, summary)
+}
+
+func TestBuildHTMLReport(t *testing.T) {
+	results := []PathResult{
+		testPathResult(t, "a", "set-1", "c", "set-2", []string{"a", "b", "c"}),
+		testPathResult(t, "a", "set-1", "c", "set-2", []string{"a", "d", "c"}),
+		testPathResult(t, "e", "set-1", "f", "set-2", []string{"e", "f"}),
+		testPathResult(t, "e", "set-1", "g", "set-3", []string{"e", "h", "i", "g"}),
+	}
+
+	summary := Summary{TotalPairs: 8, PairsProcessed: 8, PairsWithPaths: 3, PathsFound: 4}
+	report := buildTestReport(results, summary, maxReportResults)
+
+	if len(report.Results) != 4 || report.NumResults != 4 {
+		t.Errorf("Expected all 4 paths to be listed, got %v of %v\n", len(report.Results), report.NumResults)
+	}
+
+	if report.Percentage != "37.50" {
+		t.Errorf("Expected percentage 37.50, got %v\n", report.Percentage)
+	}
+
+	expectedBreakdown := []reportBreakdown{
+		{"set-1", "set-2", 2, 3, 1, 2, 5.0 / 3.0},
+		{"set-1", "set-3", 1, 1, 3, 3, 3.0},
+	}
+
+	if len(report.Breakdown) != len(expectedBreakdown) {
+		t.Fatalf("Expected %v data source pairs, got %v\n", len(expectedBreakdown), len(report.Breakdown))
+	}
+
+	for i, expected := range expectedBreakdown {
+		if report.Breakdown[i] != expected {
+			t.Errorf("Expected breakdown %v, got %v\n", expected, report.Breakdown[i])
+		}
+	}
+
+	expectedHistogram := []reportHistogramBar{
+		{1, 1, 50.0},
+		{2, 2, 100.0},
+		{3, 1, 50.0},
+	}
+
+	if len(report.Histogram) != len(expectedHistogram) {
+		t.Fatalf("Expected %v histogram bars, got %v\n", len(expectedHistogram), len(report.Histogram))
+	}
+
+	for i, expected := range expectedHistogram {
+		if report.Histogram[i] != expected {
+			t.Errorf("Expected histogram bar %v, got %v\n", expected, report.Histogram[i])
+		}
+	}
+}
+
+func TestBuildHTMLReportNeighbourhood(t *testing.T) {
+
+	// Every path in a neighbourhood has rank 1
+	results := []PathResult{
+		testPathResult(t, "a", "set-1", "b", PairModeNeighbourhood, []string{"a", "b"}),
+		testPathResult(t, "a", "set-1", "c", PairModeNeighbourhood, []string{"a", "b", "c"}),
+		testPathResult(t, "d", "set-1", "e", PairModeNeighbourhood, []string{"d", "e"}),
+	}
+
+	builder := newReportBuilder(maxReportResults, true)
+	for _, result := range results {
+		builder.add(result)
+	}
+
+	summary := Summary{TotalPairs: 3, PairsProcessed: 3, PairsWithPaths: 2, PathsFound: 3}
+	report := builder.build(PathConfig{}, summary)
+
+	// Each seed entity counts as one pair, as in the summary
+	expectedBreakdown := reportBreakdown{"set-1", PairModeNeighbourhood, 2, 3, 1, 2, 4.0 / 3.0}
+	if len(report.Breakdown) != 1 || report.Breakdown[0] != expectedBreakdown {
+		t.Errorf("Expected breakdown %v, got %v\n", expectedBreakdown, report.Breakdown)
+	}
+}
+
+func TestWriteHTMLReportEscaping(t *testing.T) {
+	results := []PathResult{
+		testPathResult(t, "<a>", "set-1", "b", "set-2", []string{"<a>", "b"}),
+	}
+
+	var buffer bytes.Buffer
+	if err := writeHTMLReport(&buffer, buildTestReport(results, Summary{}, maxReportResults)); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(buffer.String(), "<td><a></td>") || !strings.Contains(buffer.String(), "<td>&lt;a&gt;</td>") {
+		t.Fatal("Entity ID wasn't escaped in the report")
+	}
+
+	if strings.Contains(buffer.String(), "are listed") {
+		t.Fatal("Didn't expect a note that only some of the paths are listed")
+	}
+}
+
+func TestBuildHTMLReportLimitsPaths(t *testing.T) {
+	results := []PathResult{
+		testPathResult(t, "a", "set-1", "b", "set-2", []string{"a", "b"}),
+		testPathResult(t, "a", "set-1", "c", "set-2", []string{"a", "b", "c"}),
+		testPathResult(t, "a", "set-1", "d", "set-2", []string{"a", "b", "c", "d"}),
+	}
+
+	report := buildTestReport(results, Summary{}, 2)
+
+	if len(report.Results) != 2 || report.Results[1].DestinationEntityID != "c" || report.NumResults != 3 {
+		t.Errorf("Expected the first 2 of 3 paths to be listed, got %v of %v\n", report.Results, report.NumResults)
+	}
+
+	// The statistics cover all of the paths
+	expectedBreakdown := reportBreakdown{"set-1", "set-2", 3, 3, 1, 3, 2.0}
+	if len(report.Breakdown) != 1 || report.Breakdown[0] != expectedBreakdown {
+		t.Errorf("Expected breakdown %v, got %v\n", expectedBreakdown, report.Breakdown)
+	}
+
+	if len(report.Histogram) != 3 {
+		t.Errorf("Expected 3 histogram bars, got %v\n", report.Histogram)
+	}
+
+	var buffer bytes.Buffer
+	if err := writeHTMLReport(&buffer, report); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buffer.String(), "Only the first 2 of the 3 paths are listed.") {
+		t.Error("Expected a note that only some of the paths are listed")
+	}
+}
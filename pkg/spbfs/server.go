@@ -0,0 +1,464 @@
+package spbfs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Server answers queries about the paths between entities over HTTP. The graph is loaded once
+// and shared by all of the requests.
+type Server struct {
+	compact *CompactGraph // compact form of the graph to search
+	config  PathConfig    // config providing the defaults for the queries
+	mux     *http.ServeMux
+}
+
+// reachableResponse is the response from the reachable endpoint
+type reachableResponse struct {
+	EntityID  string   `json:"entity_id"` // entity ID of the root vertex
+	MaxDepth  int      `json:"max_depth"` // maximum number of hops from the root
+	Found     bool     `json:"found"`     // was the root vertex in the graph?
+	Reachable []string `json:"reachable"` // entity IDs within reach of the root (excluding the root)
+}
+
+// batchResponse is the response from the batch endpoint, in the same form as the json output format
+type batchResponse struct {
+	Results []PathResult `json:"results"` // paths found
+	Summary Summary      `json:"summary"` // counts from the search
+}
+
+// errorResponse is the response when a query fails
+type errorResponse struct {
+	Error string `json:"error"` // description of the error
+}
+
+// NewServer makes a server that searches the compact form of the graph, using the config for the
+// default options. The server doesn't keep the graph, so its memory can be reclaimed.
+func NewServer(g *Graph, config PathConfig) *Server {
+
+	s := &Server{
+		compact: g.Freeze(),
+		config:  config,
+		mux:     http.NewServeMux(),
+	}
+
+	s.mux.HandleFunc("/path", s.handlePath)
+	s.mux.HandleFunc("/all-paths", s.handleAllPaths)
+	s.mux.HandleFunc("/reachable", s.handleReachable)
+	s.mux.HandleFunc("/neighbours", s.handleNeighbours)
+	s.mux.HandleFunc("/batch", s.handleBatch)
+
+	return s
+}
+
+// ServeHTTP routes a request to the handler for its endpoint
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Serve loads the graph (from the input files or the snapshot) and answers queries on the
+// address until the context is cancelled
+func Serve(ctx context.Context, config PathConfig, addr string) error {
+
+	if err := config.Normalize(); err != nil {
+		return err
+	}
+
+	graph, _, err := loadGraph(config)
+	if err != nil {
+		return err
+	}
+	log.Printf("Graph has %v vertices\n", len(graph.Nodes))
+
+	server := &http.Server{
+		Addr:    addr,
+		Handler: NewServer(graph, config),
+	}
+
+	// Stop the server when the context is cancelled
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("Listening on %v\n", addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// handlePath finds the shortest path between two entities using the algorithm in the config, e.g.
+// /path?from=e-1&to=e-2&max_depth=3
+func (s *Server) handlePath(w http.ResponseWriter, r *http.Request) {
+
+	outputConfig := s.config.Output
+	outputConfig.PathMode = PathModeFirst
+
+	s.writePaths(w, r, outputConfig)
+}
+
+// handleAllPaths finds all of the paths between two entities, e.g.
+// /all-paths?from=e-1&to=e-2&max_depth=3&mode=all_simple. The mode is all_simple (the default),
+// all_shortest or k_shortest, where max_paths gives the number of paths.
+func (s *Server) handleAllPaths(w http.ResponseWriter, r *http.Request) {
+
+	outputConfig := s.config.Output
+	outputConfig.PathMode = r.URL.Query().Get("mode")
+	if len(outputConfig.PathMode) == 0 {
+		outputConfig.PathMode = PathModeAllSimple
+	}
+
+	if outputConfig.PathMode != PathModeAllSimple && outputConfig.PathMode != PathModeAllShortest &&
+		outputConfig.PathMode != PathModeKShortest {
+		writeError(w, fmt.Errorf("%w: invalid path mode: %v", ErrInvalidArgument, outputConfig.PathMode))
+		return
+	}
+
+	if err := outputConfig.checkWeightedSearch(); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	maxPaths, err := intParam(r, "max_paths", outputConfig.MaxPathsPerPair)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	outputConfig.MaxPathsPerPair = maxPaths
+
+	s.writePaths(w, r, outputConfig)
+}
+
+// writePaths finds the paths between the entities in the query using the path mode and writes
+// them as a list of path results. If the search runs out of time, then the list contains a single
+// timed out result.
+func (s *Server) writePaths(w http.ResponseWriter, r *http.Request, outputConfig OutputConfig) {
+
+	from, err := entityParam(r, "from")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	to, err := entityParam(r, "to")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// A path needs at least one hop
+	if len(from) > 0 && from == to {
+		writeError(w, fmt.Errorf("%w: from and to are the same entity: %v", ErrInvalidArgument, from))
+		return
+	}
+
+	maxDepth, err := depthParam(r, "max_depth", s.config.Output.MaxDepth)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	outputConfig.MaxDepth = maxDepth
+
+	ctx := r.Context()
+	results, err := s.findPathResults(ctx, from, to, outputConfig)
+
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		results = []PathResult{newTimedOutResult(from, defaultSourceLabel, to, defaultDestinationLabel)}
+	} else if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, results)
+}
+
+// findPathResults finds the paths between two entities, limiting the time taken to the pair
+// timeout (if any)
+func (s *Server) findPathResults(ctx context.Context, from string, to string, outputConfig OutputConfig) ([]PathResult, error) {
+
+	if timeout := outputConfig.pairTimeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	paths, err := findPaths(ctx, s.compact, from, to, outputConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]PathResult, len(paths))
+
+	for i, path := range paths {
+		results[i], err = buildPathResult(s.compact, from, defaultSourceLabel,
+			to, defaultDestinationLabel,
+			path, i+1, outputConfig)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+// handleReachable finds the entities within reach of an entity, e.g. /reachable?from=e-1&depth=2
+func (s *Server) handleReachable(w http.ResponseWriter, r *http.Request) {
+
+	from, err := entityParam(r, "from")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	depth, err := depthParam(r, "depth", s.config.Output.MaxDepth)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	found, reachable, err := s.compact.ReachableVertices(r.Context(), from, depth)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response := reachableResponse{
+		EntityID:  from,
+		MaxDepth:  depth,
+		Found:     found,
+		Reachable: []string{},
+	}
+
+	if found {
+		defer reachable.Release()
+
+		// The entity isn't reachable from itself
+		for _, identifier := range reachable.Identifiers() {
+			if identifier != from {
+				response.Reachable = append(response.Reachable, identifier)
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleNeighbours finds the entities between min_depth and max_depth hops from an entity, with a
+// shortest path to each, e.g. /neighbours?from=e-1&max_depth=2&max_results=10
+func (s *Server) handleNeighbours(w http.ResponseWriter, r *http.Request) {
+
+	outputConfig := s.config.Output
+
+	from, err := entityParam(r, "from")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if outputConfig.MaxDepth, err = depthParam(r, "max_depth", s.config.Output.MaxDepth); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	for _, param := range []struct {
+		name  string
+		value *int
+	}{
+		{"min_depth", &outputConfig.MinDepth},
+		{"max_results", &outputConfig.MaxResultsPerSeed},
+	} {
+		if *param.value, err = intParam(r, param.name, *param.value); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	unit := workUnit{
+		source:                from,
+		sourceDataSource:      defaultSourceLabel,
+		destinationDataSource: PairModeNeighbourhood,
+		neighbourhood:         true,
+	}
+
+	ctx := r.Context()
+	results, err := findNeighbourhoodResults(ctx, s.compact, unit, SliceToSet(s.config.Entities.Skip), outputConfig)
+
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		results = []PathResult{newTimedOutResult(unit.source, unit.sourceDataSource, "", unit.destinationDataSource)}
+	} else if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, results)
+}
+
+// handleBatch finds the paths between the pairs of entities from data sources posted in the same
+// form as the entities in the config
+func (s *Server) handleBatch(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: "batch queries must be posted"})
+		return
+	}
+
+	var entityConfig EntityConfig
+	if err := json.NewDecoder(r.Body).Decode(&entityConfig); err != nil {
+		writeError(w, fmt.Errorf("%w: unable to parse the entities: %v", ErrInvalidArgument, err))
+		return
+	}
+
+	// The server doesn't read files on behalf of the client
+	if len(entityConfig.PairsFile) > 0 {
+		writeError(w, fmt.Errorf("%w: pairs_file isn't supported by the server", ErrInvalidArgument))
+		return
+	}
+
+	for _, dataSource := range entityConfig.DataSources {
+		for _, entityID := range dataSource.EntityIds {
+			if isDocumentVertex(entityID) {
+				writeError(w, fmt.Errorf("%w: invalid entity ID %q", ErrInvalidArgument, entityID))
+				return
+			}
+		}
+	}
+
+	pairMode := entityConfig.pairMode()
+	if pairMode != PairModeCross && pairMode != PairModeWithin && pairMode != PairModeAll &&
+		pairMode != PairModeNeighbourhood {
+		writeError(w, fmt.Errorf("%w: invalid pair mode: %v", ErrInvalidArgument, pairMode))
+		return
+	}
+
+	response := batchResponse{
+		Results: []PathResult{},
+		Summary: Summary{TotalPairs: totalNumberOfPairs(&entityConfig.DataSources, pairMode)},
+	}
+
+	if response.Summary.TotalPairs == 0 {
+		writeError(w, ErrNoPairs)
+		return
+	}
+
+	// Keep the results in the same order as a single worker would produce them
+	units := buildWorkUnits(entityConfig)
+	unitResults := make([]unitResult, len(units))
+
+	var err error
+	for result := range processWorkUnits(r.Context(), s.compact, units, SliceToSet(entityConfig.Skip), s.config.Output) {
+		if result.err != nil && err == nil {
+			err = result.err
+		}
+		unitResults[result.index] = result
+	}
+
+	if err == nil {
+		err = r.Context().Err()
+	}
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	for _, result := range unitResults {
+		response.Results = append(response.Results, result.results...)
+		response.Summary.PairsProcessed += result.pairsProcessed
+		response.Summary.PairsWithPaths += result.pairsWithPaths
+		response.Summary.PathsFound += len(result.results) - result.pairsTimedOut
+		response.Summary.PairsTimedOut += result.pairsTimedOut
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// entityParam returns the value of an entity ID query parameter. An entity ID can't be mistaken
+// for a virtual document vertex.
+func entityParam(r *http.Request, name string) (string, error) {
+
+	entityID := r.URL.Query().Get(name)
+	if isDocumentVertex(entityID) {
+		return "", fmt.Errorf("%w: invalid entity ID for %v: %q", ErrInvalidArgument, name, entityID)
+	}
+
+	return entityID, nil
+}
+
+// intParam returns the value of an integer query parameter, or the default if it isn't given
+func intParam(r *http.Request, name string, defaultValue int) (int, error) {
+
+	value := r.URL.Query().Get(name)
+	if len(value) == 0 {
+		return defaultValue, nil
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v must be an integer, got %v", ErrInvalidArgument, name, value)
+	}
+
+	return i, nil
+}
+
+// depthParam returns the value of a depth query parameter, or the configured maximum depth if it
+// isn't given. The depth is limited to the configured maximum depth, so that a query can't search
+// more of the graph than a run would.
+func depthParam(r *http.Request, name string, maxDepth int) (int, error) {
+
+	depth, err := intParam(r, name, maxDepth)
+	if err != nil {
+		return 0, err
+	}
+
+	if depth < 0 {
+		return 0, fmt.Errorf("%w: %v must not be negative, got %v", ErrInvalidDepth, name, depth)
+	}
+
+	if depth > maxDepth {
+		return maxDepth, nil
+	}
+
+	return depth, nil
+}
+
+// errorStatus returns the HTTP status code for an error
+func errorStatus(err error) int {
+
+	for _, clientErr := range []error{ErrEmptyVertex, ErrInvalidDepth, ErrInvalidCost,
+		ErrInvalidArgument, ErrNoPairs} {
+
+		if errors.Is(err, clientErr) {
+			return http.StatusBadRequest
+		}
+	}
+
+	return http.StatusInternalServerError
+}
+
+// writeError writes the error as JSON with the status code for the error
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, errorStatus(err), errorResponse{Error: err.Error()})
+}
+
+// writeJSON writes the value as the JSON body of the response
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		log.Printf("Unable to write response: %v\n", err)
+	}
+}
@@ -0,0 +1,284 @@
+package spbfs
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testServer starts a server for the full test data
+func testServer(t *testing.T) *httptest.Server {
+
+	config, err := ReadConfig("./test/test-data-full/config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := readTestGraph(t, []string{
+		"./test/test-data-full/entity_doc_1.csv",
+		"./test/test-data-full/entity_doc_2.csv",
+		"./test/test-data-full/entity_doc_3.csv",
+	})
+
+	server := httptest.NewServer(NewServer(g, config))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// getJSON makes a request to the server, checks the status code and decodes the JSON response
+func getJSON(t *testing.T, server *httptest.Server, method string, path string, body string, status int, response interface{}) {
+
+	t.Helper()
+
+	request, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := server.Client().Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != status {
+		t.Fatalf("%v: expected status %v, got %v\n", path, status, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		t.Fatalf("%v: unable to decode the response: %v\n", path, err)
+	}
+}
+
+func TestServerPath(t *testing.T) {
+	server := testServer(t)
+
+	var results []PathResult
+	getJSON(t, server, http.MethodGet, "/path?from=e-3&to=e-11&max_depth=3", "", http.StatusOK, &results)
+
+	expected := []PathResult{{
+		SourceEntityID:              "e-3",
+		SourceEntityDataSource:      defaultSourceLabel,
+		DestinationEntityID:         "e-11",
+		DestinationEntityDataSource: defaultDestinationLabel,
+		NumberOfHops:                2,
+		Cost:                        2,
+		Path:                        []string{"e-3", "e-8", "e-11"},
+		WebAppLink:                  "http://192.168.99.100:8080/show/e-3,e-8,e-11",
+		Documents:                   [][]string{{"d-600"}, {"d-700"}},
+		Mode:                        PathModeFirst,
+		Rank:                        1,
+	}}
+
+	if !reflect.DeepEqual(expected, results) {
+		t.Errorf("Expected %v, got %v\n", expected, results)
+	}
+
+	// Too few hops to reach the destination
+	getJSON(t, server, http.MethodGet, "/path?from=e-3&to=e-11&max_depth=1", "", http.StatusOK, &results)
+	if len(results) != 0 {
+		t.Errorf("Expected no paths, got %v\n", results)
+	}
+}
+
+func TestServerAllPaths(t *testing.T) {
+	server := testServer(t)
+
+	var results []PathResult
+	getJSON(t, server, http.MethodGet, "/all-paths?from=e-3&to=e-11&max_depth=3", "", http.StatusOK, &results)
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 paths, got %v\n", results)
+	}
+
+	for i, result := range results {
+		if result.Mode != PathModeAllSimple || result.Rank != i+1 {
+			t.Errorf("Unexpected path result %v\n", result)
+		}
+	}
+
+	getJSON(t, server, http.MethodGet, "/all-paths?from=e-3&to=e-11&mode=k_shortest&max_paths=1", "", http.StatusOK, &results)
+	if len(results) != 1 || results[0].Mode != PathModeKShortest {
+		t.Errorf("Expected a single k shortest path, got %v\n", results)
+	}
+}
+
+func TestServerAllPathsWeighted(t *testing.T) {
+	config, err := ReadConfig("./test/test-data-full/config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Output.Algorithm = AlgorithmDijkstra
+
+	g := readTestGraph(t, []string{"./test/test-data-full/entity_doc_1.csv"})
+	server := httptest.NewServer(NewServer(g, config))
+	t.Cleanup(server.Close)
+
+	// The path modes of all-paths count the hops, so they can't be used with Dijkstra's algorithm
+	var response errorResponse
+	getJSON(t, server, http.MethodGet, "/all-paths?from=e-3&to=e-11", "", http.StatusBadRequest, &response)
+
+	if len(response.Error) == 0 {
+		t.Error("Expected an error message")
+	}
+}
+
+func TestServerReachable(t *testing.T) {
+	server := testServer(t)
+
+	var response reachableResponse
+	getJSON(t, server, http.MethodGet, "/reachable?from=e-8&depth=1", "", http.StatusOK, &response)
+
+	if !response.Found || response.MaxDepth != 1 {
+		t.Fatalf("Unexpected response %v\n", response)
+	}
+
+	for _, entity := range []string{"e-3", "e-11"} {
+		if !SliceToSet(response.Reachable).Has(entity) {
+			t.Errorf("Expected %v to be reachable, got %v\n", entity, response.Reachable)
+		}
+	}
+
+	if SliceToSet(response.Reachable).Has("e-8") {
+		t.Errorf("The root shouldn't be reachable from itself\n")
+	}
+
+	getJSON(t, server, http.MethodGet, "/reachable?from=e-999", "", http.StatusOK, &response)
+	if response.Found || len(response.Reachable) != 0 {
+		t.Errorf("Expected the entity not to be found, got %v\n", response)
+	}
+}
+
+func TestServerReachableWithoutDocumentVertices(t *testing.T) {
+	connections := largeDocumentConnections()
+
+	g, err := BipartiteToUnipartite(&connections, 3, LargeDocumentStar)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(NewServer(g, PathConfig{Output: OutputConfig{MaxDepth: 3}}))
+	t.Cleanup(server.Close)
+
+	var response reachableResponse
+	getJSON(t, server, http.MethodGet, "/reachable?from=e-1", "", http.StatusOK, &response)
+
+	expected := []string{"e-2", "e-3", "e-4", "e-5"}
+	if !reflect.DeepEqual(expected, response.Reachable) {
+		t.Errorf("Expected %v, got %v\n", expected, response.Reachable)
+	}
+}
+
+func TestServerNeighbours(t *testing.T) {
+	server := testServer(t)
+
+	var results []PathResult
+	getJSON(t, server, http.MethodGet, "/neighbours?from=e-8&max_depth=2&max_results=3", "", http.StatusOK, &results)
+
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %v\n", results)
+	}
+
+	for _, result := range results {
+		if result.SourceEntityID != "e-8" || result.Mode != PairModeNeighbourhood || result.NumberOfHops > 2 {
+			t.Errorf("Unexpected result %v\n", result)
+		}
+	}
+}
+
+func TestServerDepthLimit(t *testing.T) {
+	server := testServer(t)
+
+	// The depth of a query is limited to the maximum depth in the config (3)
+	var response reachableResponse
+	getJSON(t, server, http.MethodGet, "/reachable?from=e-8&depth=1000000", "", http.StatusOK, &response)
+
+	if !response.Found || response.MaxDepth != 3 {
+		t.Errorf("Expected the depth to be limited to 3, got %v\n", response)
+	}
+
+	var results []PathResult
+	getJSON(t, server, http.MethodGet, "/neighbours?from=e-8&max_depth=1000000", "", http.StatusOK, &results)
+
+	for _, result := range results {
+		if result.NumberOfHops > 3 {
+			t.Errorf("Expected at most 3 hops, got %v\n", result)
+		}
+	}
+
+	getJSON(t, server, http.MethodGet, "/path?from=e-3&to=e-11&max_depth=1000000", "", http.StatusOK, &results)
+	if len(results) != 1 {
+		t.Errorf("Expected a path, got %v\n", results)
+	}
+}
+
+func TestServerBatch(t *testing.T) {
+	server := testServer(t)
+
+	body := `{
+		"data_sources": [
+			{"name": "set-1", "entity_ids": ["e-3", "e-8"]},
+			{"name": "set-2", "entity_ids": ["e-11", "e-999"]}
+		]
+	}`
+
+	var response batchResponse
+	getJSON(t, server, http.MethodPost, "/batch", body, http.StatusOK, &response)
+
+	expected := Summary{TotalPairs: 4, PairsProcessed: 4, PairsWithPaths: 2, PathsFound: 2}
+	if !reflect.DeepEqual(expected, response.Summary) {
+		t.Errorf("Expected %v, got %v\n", expected, response.Summary)
+	}
+
+	if len(response.Results) != 2 || response.Results[0].SourceEntityID != "e-3" ||
+		response.Results[1].SourceEntityID != "e-8" {
+		t.Errorf("Unexpected results %v\n", response.Results)
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	server := testServer(t)
+
+	testCases := []struct {
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{http.MethodGet, "/path?to=e-11", "", http.StatusBadRequest},
+		{http.MethodGet, "/path?from=e-3&to=e-11&max_depth=x", "", http.StatusBadRequest},
+		{http.MethodGet, "/path?from=e-3&to=e-11&max_depth=-1", "", http.StatusBadRequest},
+		{http.MethodGet, "/path?from=e-3&to=e-3", "", http.StatusBadRequest},
+		{http.MethodGet, "/all-paths?from=e-3&to=e-3", "", http.StatusBadRequest},
+		{http.MethodGet, "/all-paths?from=e-3&to=e-11&mode=unknown", "", http.StatusBadRequest},
+		{http.MethodGet, "/all-paths?from=e-3&to=e-11&mode=k_shortest", "", http.StatusBadRequest},
+		{http.MethodGet, "/reachable?depth=1", "", http.StatusBadRequest},
+		{http.MethodGet, "/reachable?from=e-8&depth=x", "", http.StatusBadRequest},
+		{http.MethodGet, "/reachable?from=e-8&depth=-1", "", http.StatusBadRequest},
+		{http.MethodGet, "/neighbours?from=e-8&max_depth=x", "", http.StatusBadRequest},
+		{http.MethodGet, "/neighbours?from=e-8&max_depth=-1", "", http.StatusBadRequest},
+		{http.MethodGet, "/neighbours?from=e-8&min_depth=-1", "", http.StatusBadRequest},
+		{http.MethodGet, "/neighbours?from=e-8&min_depth=3&max_depth=2", "", http.StatusBadRequest},
+		{http.MethodGet, "/batch", "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/batch", "{", http.StatusBadRequest},
+		{http.MethodPost, "/batch", `{"data_sources": []}`, http.StatusBadRequest},
+		{http.MethodPost, "/batch", `{"pairs_file": "pairs.csv"}`, http.StatusBadRequest},
+		{http.MethodGet, "/path?from=%00doc:d-1&to=e-11", "", http.StatusBadRequest},
+		{http.MethodGet, "/reachable?from=%00doc:d-1", "", http.StatusBadRequest},
+		{http.MethodGet, "/neighbours?from=%00doc:d-1", "", http.StatusBadRequest},
+		{http.MethodPost, "/batch", `{"data_sources": [{"name": "set-1", "entity_ids": ["\u0000doc:d-1", "e-3"]}]}`, http.StatusBadRequest},
+	}
+
+	for _, testCase := range testCases {
+		var response errorResponse
+		getJSON(t, server, testCase.method, testCase.path, testCase.body, testCase.status, &response)
+
+		if len(response.Error) == 0 {
+			t.Errorf("%v %v: expected an error message\n", testCase.method, testCase.path)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package spbfs
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	"github.com/golang-collections/collections/set"
+)
+
+// snapshotMagic identifies a graph snapshot file
+const snapshotMagic = "SPBFS-SNAPSHOT"
+
+// snapshotVersion is incremented whenever the layout of the snapshot file changes
+const snapshotVersion uint32 = 2
+
+// InputFileHash records the hash of an input file used to build a graph
+type InputFileHash struct {
+	Path           string  // location of the CSV file
+	Weight         float64 // weight of the documents in the file
+	Delimiter      string  // delimiter between the fields
+	EntityColumn   string  // name of the entity ID column (if any)
+	DocumentColumn string  // name of the document ID column (if any)
+	HasHeader      bool    // does the first row contain the column names?
+	Comment        string  // character at the start of a line to ignore
+	InputType      string  // contents of the file
+	Directed       bool    // are the edges in the file directed?
+	Hash           string  // SHA-256 hash of the contents of the file
+}
+
+// SnapshotKey identifies the inputs and settings used to build a graph. A snapshot can only be
+// used if its key matches the key for the current config.
+type SnapshotKey struct {
+	Inputs                 []InputFileHash // input files in the order given in the config
+	Skip                   []string        // sorted entities skipped when reading the input files
+	MaxEntitiesPerDocument int             // maximum number of entities in a document
+	LargeDocumentPolicy    string          // policy for larger documents
+	EdgeWeight             string          // scheme for the edge weights
+}
+
+// graphSnapshot is the encoded form of a graph
+type graphSnapshot struct {
+	Key       SnapshotKey
+	Nodes     map[string][]string
+	Documents map[string][]string
+	Weights   map[string]float64
+}
+
+// hashFile returns the SHA-256 hash of the contents of a file
+func hashFile(filepath string) (string, error) {
+
+	file, err := os.Open(filepath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// NewSnapshotKey builds the key for the graph built from a config
+func NewSnapshotKey(config PathConfig) (SnapshotKey, error) {
+
+	// Empty lists are left as nil to match a decoded key
+	key := SnapshotKey{
+		Skip:                   append([]string(nil), config.Entities.Skip...),
+		MaxEntitiesPerDocument: config.Entities.MaxEntitiesPerDocument,
+		LargeDocumentPolicy:    config.Entities.LargeDocumentPolicy,
+		EdgeWeight:             config.Output.EdgeWeight,
+	}
+
+	sort.Strings(key.Skip)
+
+	for _, file := range config.InputFiles {
+
+		// Standard input can't be read twice, so it can't be checked
+		if file.Path == StdinPath {
+			return SnapshotKey{}, fmt.Errorf("%w: an input file read from standard input can't be used with a snapshot", ErrInvalidArgument)
+		}
+
+		hash, err := hashFile(file.Path)
+		if err != nil {
+			return SnapshotKey{}, fmt.Errorf("unable to hash input file %v: %v", file.Path, err)
+		}
+
+		key.Inputs = append(key.Inputs, InputFileHash{
+			Path:           file.Path,
+			Weight:         file.Weight,
+			Delimiter:      file.delimiter(),
+			EntityColumn:   file.EntityColumn,
+			DocumentColumn: file.DocumentColumn,
+			HasHeader:      file.hasHeader(),
+			Comment:        file.Comment,
+			InputType:      file.inputType(),
+			Directed:       file.Directed,
+			Hash:           hash,
+		})
+	}
+
+	return key, nil
+}
+
+// setsToSlices converts a map of sets to a map of sorted slices
+func setsToSlices(m map[string]*set.Set) map[string][]string {
+
+	result := make(map[string][]string, len(m))
+	for k, v := range m {
+		result[k] = ConvertSetToSlice(v)
+	}
+
+	return result
+}
+
+// slicesToSets converts a map of slices to a map of sets
+func slicesToSets(m map[string][]string) map[string]*set.Set {
+
+	result := make(map[string]*set.Set, len(m))
+	for k, v := range m {
+		result[k] = SliceToSet(v)
+	}
+
+	return result
+}
+
+// WriteSnapshot writes the graph and the key of the inputs used to build it to a binary file. The
+// file starts with a magic string and the format version, followed by the gob-encoded graph.
+func WriteSnapshot(filePath string, g *Graph, key SnapshotKey) error {
+
+	// Write to a temporary file so that an interrupted write doesn't leave a corrupt snapshot
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), filepath.Base(filePath)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	writer := bufio.NewWriter(tmp)
+
+	snapshot := graphSnapshot{
+		Key:       key,
+		Nodes:     setsToSlices(g.Nodes),
+		Documents: setsToSlices(g.Documents),
+		Weights:   g.Weights,
+	}
+
+	if _, err := writer.WriteString(snapshotMagic); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := binary.Write(writer, binary.BigEndian, snapshotVersion); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := gob.NewEncoder(writer).Encode(&snapshot); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filePath)
+}
+
+// ReadSnapshot reads a graph and the key of the inputs used to build it from a binary file
+func ReadSnapshot(filePath string) (*Graph, SnapshotKey, error) {
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, SnapshotKey{}, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+
+	// Check the magic string and the version
+	magic := make([]byte, len(snapshotMagic))
+	if _, err := io.ReadFull(reader, magic); err != nil || string(magic) != snapshotMagic {
+		return nil, SnapshotKey{}, fmt.Errorf("%v is not a graph snapshot", filePath)
+	}
+
+	var version uint32
+	if err := binary.Read(reader, binary.BigEndian, &version); err != nil {
+		return nil, SnapshotKey{}, err
+	}
+
+	if version != snapshotVersion {
+		return nil, SnapshotKey{}, fmt.Errorf("snapshot %v has version %v, expected %v", filePath, version, snapshotVersion)
+	}
+
+	// Decode the graph
+	snapshot := graphSnapshot{}
+	if err := gob.NewDecoder(reader).Decode(&snapshot); err != nil {
+		return nil, SnapshotKey{}, fmt.Errorf("unable to decode snapshot %v: %v", filePath, err)
+	}
+
+	g := NewGraph()
+	g.Nodes = slicesToSets(snapshot.Nodes)
+	g.Documents = slicesToSets(snapshot.Documents)
+	if snapshot.Weights != nil {
+		g.Weights = snapshot.Weights
+	}
+
+	return &g, snapshot.Key, nil
+}
+
+// loadSnapshot reads the graph from the snapshot if it was built from the same inputs and settings
+func loadSnapshot(filePath string, key SnapshotKey) (*Graph, error) {
+
+	g, snapshotKey, err := ReadSnapshot(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if !reflect.DeepEqual(key, snapshotKey) {
+		return nil, fmt.Errorf("snapshot %v was built from different inputs or settings", filePath)
+	}
+
+	return g, nil
+}
@@ -0,0 +1,175 @@
+package spbfs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// snapshotTestConfig returns a config that reads copies of the full test data in a temporary directory
+func snapshotTestConfig(t *testing.T) PathConfig {
+
+	dir := t.TempDir()
+	config := PathConfig{
+		SnapshotFile: filepath.Join(dir, "graph.snapshot"),
+		Entities: EntityConfig{
+			Skip: []string{"e-4"},
+		},
+		Output: OutputConfig{
+			EdgeWeight: WeightCount,
+		},
+	}
+
+	for _, name := range []string{"entity_doc_1.csv", "entity_doc_2.csv", "entity_doc_3.csv"} {
+		contents, err := ioutil.ReadFile(filepath.Join("./test/test-data-full", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, contents, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		config.InputFiles = append(config.InputFiles, InputFile{Path: path, Weight: 1.0})
+	}
+
+	return config
+}
+
+func assertSameGraph(t *testing.T, expected *Graph, actual *Graph) {
+
+	if !reflect.DeepEqual(setsToSlices(expected.Nodes), setsToSlices(actual.Nodes)) {
+		t.Fatal("Vertices and edges differ")
+	}
+
+	if !reflect.DeepEqual(setsToSlices(expected.Documents), setsToSlices(actual.Documents)) {
+		t.Fatal("Edge documents differ")
+	}
+
+	if !reflect.DeepEqual(expected.Weights, actual.Weights) {
+		t.Fatalf("Edge weights differ: %v and %v\n", expected.Weights, actual.Weights)
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	config := snapshotTestConfig(t)
+	expected, err := buildGraph(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := NewSnapshotKey(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteSnapshot(config.SnapshotFile, expected, key); err != nil {
+		t.Fatal(err)
+	}
+
+	actual, actualKey, err := ReadSnapshot(config.SnapshotFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(key, actualKey) {
+		t.Fatalf("Expected key %v, got %v\n", key, actualKey)
+	}
+
+	assertSameGraph(t, expected, actual)
+}
+
+func TestReadSnapshotInvalid(t *testing.T) {
+	config := snapshotTestConfig(t)
+
+	// Not a snapshot
+	if _, _, err := ReadSnapshot(config.InputFiles[0].Path); err == nil {
+		t.Fatal("Expected an error reading a CSV file as a snapshot")
+	}
+
+	// Different version, including a snapshot written before the input file layout was recorded
+	for _, version := range []byte{1, 99} {
+		contents := append([]byte(snapshotMagic), 0, 0, 0, version)
+		if err := ioutil.WriteFile(config.SnapshotFile, contents, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, _, err := ReadSnapshot(config.SnapshotFile); err == nil {
+			t.Fatalf("Expected an error reading a snapshot with version %v\n", version)
+		}
+	}
+}
+
+func TestLoadGraphUsesSnapshot(t *testing.T) {
+	config := snapshotTestConfig(t)
+
+	// The first run builds the graph and writes the snapshot
+	built, fromSnapshot, err := loadGraph(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fromSnapshot {
+		t.Fatal("Graph shouldn't be read from a snapshot that doesn't exist")
+	}
+
+	if _, err := os.Stat(config.SnapshotFile); err != nil {
+		t.Fatalf("Snapshot wasn't written: %v\n", err)
+	}
+
+	// The second run reads the snapshot
+	loaded, fromSnapshot, err := loadGraph(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fromSnapshot {
+		t.Fatal("Graph should be read from the snapshot")
+	}
+
+	assertSameGraph(t, built, loaded)
+}
+
+func TestLoadGraphRebuildsSnapshot(t *testing.T) {
+	config := snapshotTestConfig(t)
+	if _, _, err := loadGraph(config); err != nil {
+		t.Fatal(err)
+	}
+
+	// Changing the settings invalidates the snapshot
+	config.Output.EdgeWeight = WeightUnit
+	if _, fromSnapshot, _ := loadGraph(config); fromSnapshot {
+		t.Fatal("Snapshot should be rebuilt when the edge weight scheme changes")
+	}
+
+	if _, fromSnapshot, _ := loadGraph(config); !fromSnapshot {
+		t.Fatal("Rebuilt snapshot should be used")
+	}
+
+	// Changing the layout of an input file invalidates the snapshot
+	config.InputFiles[0].Comment = "#"
+	if _, fromSnapshot, _ := loadGraph(config); fromSnapshot {
+		t.Fatal("Snapshot should be rebuilt when the layout of an input file changes")
+	}
+
+	// Changing an input file invalidates the snapshot
+	file, err := os.OpenFile(config.InputFiles[2].Path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString("\ne-1,d-999\ne-2,d-999\n")
+	file.Close()
+
+	graph, fromSnapshot, err := loadGraph(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fromSnapshot {
+		t.Fatal("Snapshot should be rebuilt when an input file changes")
+	}
+
+	if !graph.Nodes["e-1"].Has("e-2") {
+		t.Fatal("Rebuilt graph doesn't contain the new edge")
+	}
+}
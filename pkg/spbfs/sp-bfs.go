@@ -0,0 +1,1320 @@
+// Package spbfs finds the shortest paths between entities that are connected through the
+// documents they appear in, by transforming the bipartite entity-document graph to a unipartite
+// entity graph and searching it with a limited Breadth First Search.
+package spbfs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DataSource represents a named data source with entity IDs
+type DataSource struct {
+	Name      string   `json:"name"`       // friendly name of the data source
+	EntityIds []string `json:"entity_ids"` // list of entity IDs
+	SelfPairs bool     `json:"self_pairs"` // should pairs of entities within the data source be searched?
+}
+
+// Modes for the pairs of entities to search between
+const (
+	PairModeCross         = "cross"         // pairs of entities from different data sources
+	PairModeWithin        = "within"        // pairs of entities from the same data source
+	PairModeAll           = "all"           // pairs of entities from different and the same data sources
+	PairModeNeighbourhood = "neighbourhood" // every entity within reach of each entity in the data sources
+)
+
+// hasCrossPairs returns true if the pairs of entities from different data sources are searched
+func hasCrossPairs(pairMode string) bool {
+	return pairMode == PairModeCross || pairMode == PairModeAll
+}
+
+// hasWithinPairs returns true if the pairs of entities within the data source are searched
+func (d *DataSource) hasWithinPairs(pairMode string) bool {
+	return pairMode != PairModeNeighbourhood && (d.SelfPairs || pairMode == PairModeWithin || pairMode == PairModeAll)
+}
+
+// EntityConfig represents the entity pairs for which to find paths
+type EntityConfig struct {
+	DataSources            []DataSource `json:"data_sources"`              // list of data sources with entity IDs of interest
+	Skip                   []string     `json:"skip"`                      // list of entities to ignore when constructing the graph
+	MaxEntitiesPerDocument int          `json:"max_entities_per_document"` // maximum number of entities in a document (0 = no limit)
+	LargeDocumentPolicy    string       `json:"large_document_policy"`     // policy for larger documents: skip, star or clique
+	PairsFile              string       `json:"pairs_file"`                // CSV file of the entity pairs to search (instead of the data sources)
+	PairMode               string       `json:"pair_mode"`                 // pairs to search: cross, within, all or neighbourhood (default cross)
+}
+
+// Algorithms for finding the shortest path between a pair of entities
+const (
+	AlgorithmBfs           = "bfs"           // Breadth First Search (fewest hops)
+	AlgorithmBidirectional = "bidirectional" // Breadth First Search from both ends (fewest hops)
+	AlgorithmDijkstra      = "dijkstra"      // Dijkstra's algorithm (least cost using the edge weights)
+)
+
+// Modes for the paths to find between a pair of entities
+const (
+	PathModeFirst       = "first"        // the first shortest path found by the algorithm
+	PathModeAllShortest = "all_shortest" // all paths with the minimum number of hops
+	PathModeAllSimple   = "all_simple"   // all paths without repeated vertices up to the maximum depth
+	PathModeKShortest   = "k_shortest"   // up to max_paths_per_pair loopless paths with the fewest hops
+)
+
+// OutputConfig represents the config for the output from the BFS
+type OutputConfig struct {
+	MaxDepth          int     `json:"max_depth"`            // maximum number of hops from a source to a destination vertex
+	MinDepth          int     `json:"min_depth"`            // minimum number of hops to a reported entity in neighbourhood mode
+	MaxResultsPerSeed int     `json:"max_results_per_seed"` // maximum number of entities reported per seed in neighbourhood mode (0 = no limit)
+	Algorithm         string  `json:"algorithm"`            // shortest path algorithm: bfs, bidirectional or dijkstra
+	EdgeWeight        string  `json:"edge_weight"`          // scheme for the edge weights: unit, count, jaccard or file
+	MaxCost           float64 `json:"max_cost"`             // maximum cost of a path for Dijkstra's algorithm (0 = no limit)
+	FindAllPaths      bool    `json:"find_all_paths"`       // should all shortest paths be found or just the first?
+	PathMode          string  `json:"path_mode"`            // paths to find: first, all_shortest, all_simple or k_shortest
+	MaxPathsPerPair   int     `json:"max_paths_per_pair"`   // number of paths to find for each pair in k_shortest mode
+	OutputFile        string  `json:"output_file"`          // location of the output CSV file
+	OutputFormat      string  `json:"output_format"`        // format of the output file: csv, jsonl or json (default csv)
+	SubgraphFile      string  `json:"subgraph_file"`        // location of the subgraph of the paths found (optional)
+	SubgraphFormat    string  `json:"subgraph_format"`      // format of the subgraph: graphml, gexf or dot (default from the file extension)
+	HTMLReport        bool    `json:"html_report"`          // write an HTML report of the results next to the output file
+	OutputDelimiter   string  `json:"delimiter"`            // delimiter to use in the CSV file
+	PathDelimiter     string  `json:"path_delimiter"`       // delimiter to use between entity IDs on a path
+	WebAppLink        string  `json:"webapp_link"`          // web-app link to generate for the path
+	UnipartiteFile    string  `json:"unipartite"`           // location of the unipartite CSV file to write
+	Workers           int     `json:"workers"`              // number of workers finding paths in parallel (default 1)
+	Ordered           bool    `json:"ordered"`              // write the results in the same order as a single worker
+	PairTimeout       string  `json:"pair_timeout"`         // maximum time to search for the paths between a pair, e.g. 30s (optional)
+	Resume            bool    `json:"resume"`               // continue an earlier run from its checkpoint, appending to the output file
+}
+
+// InputFile represents an entity-document CSV file. In the JSON config it is either a string
+// containing the path or an object with the path, the weight of its documents and its layout.
+type InputFile struct {
+	Path           string  `json:"path"`            // location of the CSV file
+	Weight         float64 `json:"weight"`          // weight of the documents in the file (default 1)
+	Delimiter      string  `json:"delimiter"`       // delimiter between the fields (default comma)
+	EntityColumn   string  `json:"entity_column"`   // name of the entity ID column in the header (default first column)
+	DocumentColumn string  `json:"document_column"` // name of the document ID column in the header (default second column)
+	HasHeader      *bool   `json:"has_header"`      // does the first row contain the column names? (default true)
+	Comment        string  `json:"comment"`         // character at the start of a line to ignore (optional)
+	InputType      string  `json:"input_type"`      // contents of the file: entity_document (default) or edges
+	Directed       bool    `json:"directed"`        // are the edges in an edge list directed from source to destination?
+}
+
+// Types of input file
+const (
+	InputTypeEntityDocument = "entity_document" // entity-document relationships
+	InputTypeEdges          = "edges"           // entity-entity edges in the format written for the unipartite graph
+)
+
+// UnmarshalJSON reads an input file from either a string or an object
+func (f *InputFile) UnmarshalJSON(data []byte) error {
+
+	// Just the path to the file
+	var path string
+	if err := json.Unmarshal(data, &path); err == nil {
+		*f = InputFile{Path: path, Weight: 1.0}
+		return nil
+	}
+
+	// Object with the path, weight and layout
+	type inputFileObject InputFile
+	obj := inputFileObject{Weight: 1.0}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+
+	*f = InputFile(obj)
+	return nil
+}
+
+// delimiter returns the delimiter between the fields of the file
+func (f *InputFile) delimiter() string {
+	if len(f.Delimiter) == 0 {
+		return ","
+	}
+	return f.Delimiter
+}
+
+// inputType returns the type of the contents of the file
+func (f *InputFile) inputType() string {
+	if len(f.InputType) == 0 {
+		return InputTypeEntityDocument
+	}
+	return f.InputType
+}
+
+// hasHeader returns true if the first row of the file contains the column names. Edge lists don't
+// have a header by default, matching the unipartite graph file.
+func (f *InputFile) hasHeader() bool {
+	if f.HasHeader == nil {
+		return f.inputType() != InputTypeEdges
+	}
+	return *f.HasHeader
+}
+
+// namedColumns returns true if the entity and document columns are found by name in the header
+func (f *InputFile) namedColumns() bool {
+	return len(f.EntityColumn) > 0 || len(f.DocumentColumn) > 0
+}
+
+// validate checks the layout of the file
+func (f *InputFile) validate() error {
+
+	if len(f.Path) == 0 {
+		return errors.New("path is empty")
+	}
+
+	if f.inputType() != InputTypeEntityDocument && f.inputType() != InputTypeEdges {
+		return fmt.Errorf("invalid input type: %v", f.InputType)
+	}
+
+	if f.inputType() == InputTypeEdges && f.namedColumns() {
+		return errors.New("columns can't be named in an edge list")
+	}
+
+	if f.inputType() != InputTypeEdges && f.Directed {
+		return errors.New("only the edges in an edge list can be directed")
+	}
+
+	comma, err := delimiterRune(f.delimiter())
+	if err != nil {
+		return err
+	}
+
+	if len(f.Comment) > 0 {
+		comment, err := delimiterRune(f.Comment)
+		if err != nil {
+			return fmt.Errorf("comment character: %w", err)
+		}
+
+		if comment == comma {
+			return fmt.Errorf("comment character is the same as the delimiter: %q", f.Comment)
+		}
+	}
+
+	if f.namedColumns() {
+		if len(f.EntityColumn) == 0 || len(f.DocumentColumn) == 0 {
+			return errors.New("both the entity and document columns must be named")
+		}
+
+		if f.EntityColumn == f.DocumentColumn {
+			return fmt.Errorf("entity and document columns are the same: %v", f.EntityColumn)
+		}
+
+		if !f.hasHeader() {
+			return errors.New("columns can only be chosen by name if the file has a header")
+		}
+	}
+
+	return nil
+}
+
+// PathConfig represents the JSON config
+type PathConfig struct {
+	InputFiles   []InputFile  `json:"input_files"`   // list of CSV files from which the graph will be constructed
+	SnapshotFile string       `json:"snapshot_file"` // location of the binary graph snapshot (optional)
+	Entities     EntityConfig `json:"entities"`      // entity IDs to consider and skip
+	Output       OutputConfig `json:"output"`        // configuration for the output CSV file
+}
+
+// display the path config
+func (c *PathConfig) display() {
+	for _, p := range c.parameters() {
+		log.Printf("Parameter - %-27v  %v\n", p.Name+":", p.Value)
+	}
+}
+
+// parameter is a named value from the config
+type parameter struct {
+	Name  string      // name of the parameter
+	Value interface{} // value of the parameter
+}
+
+// parameters returns the parameters of the config for display, including each of the input files
+func (c *PathConfig) parameters() []parameter {
+
+	params := []parameter{{"Number of input files", len(c.InputFiles)}}
+	for i, file := range c.InputFiles {
+		params = append(params, parameter{fmt.Sprintf("Input file %v", i+1), file.Path})
+	}
+
+	return append(params, []parameter{
+		{"Graph snapshot file", c.SnapshotFile},
+		{"Number of data sources", len(c.Entities.DataSources)},
+		{"Number of entities to skip", len(c.Entities.Skip)},
+		{"Max entities per document", c.Entities.MaxEntitiesPerDocument},
+		{"Large document policy", c.Entities.LargeDocumentPolicy},
+		{"Entity pairs file", c.Entities.PairsFile},
+		{"Pair mode", c.Entities.pairMode()},
+		{"Maximum depth", c.Output.MaxDepth},
+		{"Minimum depth", c.Output.MinDepth},
+		{"Max results per seed", c.Output.MaxResultsPerSeed},
+		{"Algorithm", c.Output.Algorithm},
+		{"Edge weight scheme", c.Output.EdgeWeight},
+		{"Maximum cost", c.Output.MaxCost},
+		{"Find all paths", c.Output.FindAllPaths},
+		{"Path mode", c.Output.pathMode()},
+		{"Max paths per pair", c.Output.MaxPathsPerPair},
+		{"Output file", c.Output.OutputFile},
+		{"Output format", c.Output.outputFormat()},
+		{"Subgraph file", c.Output.SubgraphFile},
+		{"Subgraph format", c.Output.subgraphFormat()},
+		{"HTML report", c.Output.HTMLReport},
+		{"Delimiter", c.Output.OutputDelimiter},
+		{"Path delimiter", c.Output.PathDelimiter},
+		{"Web-app link template", c.Output.WebAppLink},
+		{"Unipartite graph file", c.Output.UnipartiteFile},
+		{"Number of workers", c.Output.numWorkers()},
+		{"Ordered results", c.Output.Ordered},
+		{"Pair timeout", c.Output.PairTimeout},
+		{"Resume", c.Output.Resume},
+	}...)
+}
+
+// ReadConfig reads the JSON configuration from a file and checks the options
+func ReadConfig(filePath string) (PathConfig, error) {
+
+	// Read the contents of the file
+	bytes, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return PathConfig{}, fmt.Errorf("%w: unable to read config file %v: %v", ErrConfig, filePath, err)
+	}
+
+	// Unmarshall the JSON in the config file
+	config := PathConfig{}
+	err = json.Unmarshal(bytes, &config)
+	if err != nil {
+		return PathConfig{}, fmt.Errorf("%w: unable to unmarshall JSON from file %v: %v", ErrConfig, filePath, err)
+	}
+
+	if err := config.Normalize(); err != nil {
+		return PathConfig{}, fmt.Errorf("%w in file %v", err, filePath)
+	}
+
+	return config, nil
+}
+
+// Normalize fills in the defaults, expands the glob patterns and directories in the input files
+// and checks the options in the config. It is called by ReadConfig and Run, so a config built in
+// code behaves in the same way as one read from file. Normalizing a config again doesn't change it.
+func (c *PathConfig) Normalize() error {
+
+	// Defaults
+	if len(c.Output.Algorithm) == 0 {
+		c.Output.Algorithm = AlgorithmBfs
+	}
+
+	if len(c.Output.EdgeWeight) == 0 {
+		c.Output.EdgeWeight = WeightUnit
+	}
+
+	// Expand the glob patterns and directories in the input files
+	inputFiles, err := expandInputFiles(c.InputFiles)
+	if err != nil {
+		return fmt.Errorf("%w: input files: %v", ErrConfig, err)
+	}
+	c.InputFiles = inputFiles
+
+	return c.validate()
+}
+
+// hasDirectedEdges returns true if any of the input files contain directed edges
+func (c *PathConfig) hasDirectedEdges() bool {
+	for _, file := range c.InputFiles {
+		if file.Directed {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks the options in the config
+func (c *PathConfig) validate() error {
+
+	numStdin := 0
+	for _, file := range c.InputFiles {
+		if err := file.validate(); err != nil {
+			return fmt.Errorf("%w: input file %v: %w", ErrConfig, file.Path, err)
+		}
+
+		if file.Path == StdinPath {
+			numStdin++
+		}
+	}
+
+	// Bidirectional search assumes that the graph is undirected
+	if c.hasDirectedEdges() && c.Output.Algorithm == AlgorithmBidirectional {
+		return fmt.Errorf("%w: the %v algorithm can't be used with directed edges", ErrConfig, AlgorithmBidirectional)
+	}
+
+	// Standard input can only be read once and can't be hashed for the snapshot
+	if numStdin > 1 {
+		return fmt.Errorf("%w: standard input can only be used for one input file", ErrConfig)
+	}
+
+	if numStdin > 0 && len(c.SnapshotFile) > 0 {
+		return fmt.Errorf("%w: standard input can't be used for an input file with a snapshot", ErrConfig)
+	}
+
+	if c.Output.Algorithm != AlgorithmBfs && c.Output.Algorithm != AlgorithmBidirectional &&
+		c.Output.Algorithm != AlgorithmDijkstra {
+		return fmt.Errorf("%w: invalid algorithm: %v", ErrConfig, c.Output.Algorithm)
+	}
+
+	if !validWeightScheme(c.Output.EdgeWeight) {
+		return fmt.Errorf("%w: invalid edge weight scheme: %v", ErrConfig, c.Output.EdgeWeight)
+	}
+
+	if c.Output.MaxDepth < 0 {
+		return fmt.Errorf("%w: invalid maximum depth: %v", ErrConfig, c.Output.MaxDepth)
+	}
+
+	if c.Output.MaxCost < 0 {
+		return fmt.Errorf("%w: invalid maximum cost: %v", ErrConfig, c.Output.MaxCost)
+	}
+
+	mode := c.Output.pathMode()
+	if mode != PathModeFirst && mode != PathModeAllShortest && mode != PathModeAllSimple &&
+		mode != PathModeKShortest {
+		return fmt.Errorf("%w: invalid path mode: %v", ErrConfig, mode)
+	}
+
+	if err := c.Output.checkWeightedSearch(); err != nil {
+		return fmt.Errorf("%w: %w", ErrConfig, err)
+	}
+
+	if mode == PathModeKShortest && c.Output.MaxPathsPerPair < 1 {
+		return fmt.Errorf("%w: invalid maximum number of paths per pair: %v", ErrConfig, c.Output.MaxPathsPerPair)
+	}
+
+	format := c.Output.outputFormat()
+	if format != OutputFormatCSV && format != OutputFormatJSONL && format != OutputFormatJSON {
+		return fmt.Errorf("%w: invalid output format: %v", ErrConfig, format)
+	}
+
+	if format == OutputFormatCSV {
+		if _, err := delimiterRune(c.Output.OutputDelimiter); err != nil {
+			return fmt.Errorf("%w: %w", ErrConfig, err)
+		}
+
+		if len(c.Output.PathDelimiter) == 0 {
+			return fmt.Errorf("%w: path delimiter is empty", ErrConfig)
+		}
+	}
+
+	if len(c.Output.SubgraphFile) > 0 {
+		subgraphFormat := c.Output.subgraphFormat()
+		if subgraphFormat != SubgraphFormatGraphML && subgraphFormat != SubgraphFormatGEXF &&
+			subgraphFormat != SubgraphFormatDOT {
+			return fmt.Errorf("%w: invalid subgraph format: %v", ErrConfig, subgraphFormat)
+		}
+	}
+
+	if len(c.Output.UnipartiteFile) > 0 {
+		if _, err := delimiterRune(c.Output.PathDelimiter); err != nil {
+			return fmt.Errorf("%w: unipartite graph file: %w", ErrConfig, err)
+		}
+	}
+
+	pairMode := c.Entities.pairMode()
+	if pairMode != PairModeCross && pairMode != PairModeWithin && pairMode != PairModeAll &&
+		pairMode != PairModeNeighbourhood {
+		return fmt.Errorf("%w: invalid pair mode: %v", ErrConfig, pairMode)
+	}
+
+	if c.Entities.MaxEntitiesPerDocument < 0 {
+		return fmt.Errorf("%w: invalid maximum number of entities per document: %v", ErrConfig, c.Entities.MaxEntitiesPerDocument)
+	}
+
+	if len(c.Entities.LargeDocumentPolicy) > 0 && !validLargeDocumentPolicy(c.Entities.LargeDocumentPolicy) {
+		return fmt.Errorf("%w: invalid policy for large documents: %v", ErrConfig, c.Entities.LargeDocumentPolicy)
+	}
+
+	if c.Output.MinDepth < 0 || c.Output.MinDepth > c.Output.MaxDepth {
+		return fmt.Errorf("%w: invalid minimum depth: %v", ErrConfig, c.Output.MinDepth)
+	}
+
+	if c.Output.MaxResultsPerSeed < 0 {
+		return fmt.Errorf("%w: invalid maximum number of results per seed: %v", ErrConfig, c.Output.MaxResultsPerSeed)
+	}
+
+	// The subgraph and report are built from the paths found in a single run
+	if c.Output.Resume && (len(c.Output.SubgraphFile) > 0 || c.Output.HTMLReport) {
+		return fmt.Errorf("%w: resume can't be used with a subgraph file or an HTML report", ErrConfig)
+	}
+
+	if len(c.Output.PairTimeout) > 0 {
+		if timeout, err := time.ParseDuration(c.Output.PairTimeout); err != nil || timeout < 0 {
+			return fmt.Errorf("%w: invalid pair timeout: %v", ErrConfig, c.Output.PairTimeout)
+		}
+	}
+
+	return nil
+}
+
+// pairMode returns the mode for the pairs of entities to search, which defaults to cross
+func (c *EntityConfig) pairMode() string {
+
+	if len(c.PairMode) > 0 {
+		return c.PairMode
+	}
+
+	return PairModeCross
+}
+
+// pathMode returns the mode for the paths to find, using max_paths_per_pair or find_all_paths
+// if the mode isn't set
+func (c *OutputConfig) pathMode() string {
+
+	if len(c.PathMode) > 0 {
+		return c.PathMode
+	}
+
+	if c.MaxPathsPerPair > 0 {
+		return PathModeKShortest
+	}
+
+	if c.FindAllPaths {
+		return PathModeAllShortest
+	}
+
+	return PathModeFirst
+}
+
+// checkWeightedSearch returns an error if a weighted search is requested with a path mode other
+// than first, as the other path modes count the hops and ignore the edge weights
+func (c *OutputConfig) checkWeightedSearch() error {
+
+	mode := c.pathMode()
+	if mode == PathModeFirst {
+		return nil
+	}
+
+	if c.Algorithm == AlgorithmDijkstra {
+		return fmt.Errorf("%w: the %v algorithm can't be used with the %v path mode", ErrInvalidArgument, AlgorithmDijkstra, mode)
+	}
+
+	if c.MaxCost > 0 {
+		return fmt.Errorf("%w: a maximum cost can't be used with the %v path mode", ErrInvalidArgument, mode)
+	}
+
+	return nil
+}
+
+// usesReachability returns true if the reachable vertices are found before searching for paths
+func (c *OutputConfig) usesReachability() bool {
+	return c.pathMode() != PathModeFirst || c.Algorithm != AlgorithmBidirectional
+}
+
+// numWorkers returns the number of workers to find paths in parallel
+func (c *OutputConfig) numWorkers() int {
+	if c.Workers < 1 {
+		return 1
+	}
+	return c.Workers
+}
+
+// pairTimeout returns the maximum time to search for the paths between a pair (0 = no limit)
+func (c *OutputConfig) pairTimeout() time.Duration {
+	timeout, _ := time.ParseDuration(c.PairTimeout)
+	return timeout
+}
+
+// costLimit returns the maximum cost of a path for a weighted search
+func (c *OutputConfig) costLimit() float64 {
+	if c.MaxCost == 0 {
+		return math.Inf(1)
+	}
+	return c.MaxCost
+}
+
+// PathResult represents a shortest path
+type PathResult struct {
+	SourceEntityID              string     `json:"source_entity_id"`        // entity ID of the source vertex
+	SourceEntityDataSource      string     `json:"source_data_source"`      // data source from which the source entity ID came
+	DestinationEntityID         string     `json:"destination_entity_id"`   // entity ID of the destination vertex
+	DestinationEntityDataSource string     `json:"destination_data_source"` // data source from which the destination entity ID came
+	NumberOfHops                int        `json:"hops"`                    // number of hops from source to destination
+	Cost                        float64    `json:"cost"`                    // total cost of the edges on the path
+	Path                        []string   `json:"path"`                    // list of entity IDs on the path from source to destination
+	WebAppLink                  string     `json:"link,omitempty"`          // web-app link for the path
+	Documents                   [][]string `json:"documents,omitempty"`     // document IDs supporting each hop of the path (if known)
+	Mode                        string     `json:"mode"`                    // path mode that produced the path
+	Rank                        int        `json:"rank"`                    // rank of the path amongst the paths found for the pair (from 1)
+}
+
+// ResultTimedOut is the mode of the result recorded for a pair whose search ran out of time
+const ResultTimedOut = "timed_out"
+
+// newTimedOutResult makes the result for a pair whose search exceeded the pair timeout. It
+// doesn't have a path.
+func newTimedOutResult(source string, sourceDataSource string,
+	destination string, destinationDataSource string) PathResult {
+
+	return PathResult{
+		SourceEntityID:              source,
+		SourceEntityDataSource:      sourceDataSource,
+		DestinationEntityID:         destination,
+		DestinationEntityDataSource: destinationDataSource,
+		Path:                        []string{},
+		Mode:                        ResultTimedOut,
+	}
+}
+
+// timedOut returns true if the search for the pair ran out of time
+func (r *PathResult) timedOut() bool {
+	return r.Mode == ResultTimedOut
+}
+
+// buildWebAppLink builds the web-app link
+func buildWebAppLink(template string, path []string) (string, error) {
+
+	// Precondition
+	if len(path) == 0 {
+		return "", ErrEmptyPath
+	}
+
+	// List of comma-separated entity IDs
+	entityIds := strings.Join(path, ",")
+
+	// Use the template to build the URL
+	return strings.Replace(template, "<ENTITY_IDS>", entityIds, -1), nil
+}
+
+// NewPathResult returns a PathResult based on a list of vertices
+func NewPathResult(source string, sourceDataSource string,
+	destination string, destinationDataSource string,
+	vertices []string, webAppTemplate string) (PathResult, error) {
+
+	// Check the parameters
+	if len(source) == 0 {
+		return PathResult{}, fmt.Errorf("%w: source entity ID", ErrEmptyVertex)
+	}
+
+	if len(sourceDataSource) == 0 {
+		return PathResult{}, fmt.Errorf("%w: data source for the source entity %v is blank", ErrInvalidArgument, source)
+	}
+
+	if len(destination) == 0 {
+		return PathResult{}, fmt.Errorf("%w: destination entity ID", ErrEmptyVertex)
+	}
+
+	if len(destinationDataSource) == 0 {
+		return PathResult{}, fmt.Errorf("%w: data source for the destination entity %v is blank", ErrInvalidArgument, destination)
+	}
+
+	if len(vertices) < 2 {
+		return PathResult{}, fmt.Errorf("%w: %v vertices from %v to %v", ErrEmptyPath, len(vertices), source, destination)
+	}
+
+	link, err := buildWebAppLink(webAppTemplate, vertices)
+	if err != nil {
+		return PathResult{}, err
+	}
+
+	return PathResult{
+		SourceEntityID:              source,
+		SourceEntityDataSource:      sourceDataSource,
+		DestinationEntityID:         destination,
+		DestinationEntityDataSource: destinationDataSource,
+		NumberOfHops:                len(vertices) - 1,
+		Cost:                        float64(len(vertices) - 1),
+		Rank:                        1,
+		Path:                        vertices,
+		WebAppLink:                  link,
+	}, nil
+}
+
+// display produces a string representation of the path for stdout
+func (r *PathResult) display() string {
+
+	if r.timedOut() {
+		return fmt.Sprintf("%v:%v -> %v:%v timed out",
+			r.SourceEntityID, r.SourceEntityDataSource,
+			r.DestinationEntityID, r.DestinationEntityDataSource)
+	}
+
+	return fmt.Sprintf("%v:%v -> %v:%v (%v hops) %v",
+		r.SourceEntityID, r.SourceEntityDataSource,
+		r.DestinationEntityID, r.DestinationEntityDataSource,
+		r.NumberOfHops, r.Path)
+}
+
+// toRecord converts a path result to the fields of a row of the CSV file
+func (r *PathResult) toRecord(pathDelimiter string) ([]string, error) {
+
+	// Precondition
+	if len(pathDelimiter) == 0 {
+		return nil, fmt.Errorf("%w: cannot use a blank delimiter for the path", ErrInvalidDelimiter)
+	}
+
+	// Build a representation of the path as a simple delimited string
+	path := strings.Join(r.Path, pathDelimiter)
+
+	return []string{
+		r.SourceEntityID,
+		r.SourceEntityDataSource,
+		r.DestinationEntityID,
+		r.DestinationEntityDataSource,
+		strconv.Itoa(r.NumberOfHops),
+		strconv.FormatFloat(r.Cost, 'g', -1, 64),
+		path,
+		r.WebAppLink,
+		formatDocuments(r.Documents, pathDelimiter),
+		r.Mode,
+		strconv.Itoa(r.Rank),
+	}, nil
+}
+
+// toString converts a path result to delimited form for writing to file, quoting fields as required
+func (r *PathResult) toString(delimiter string, pathDelimiter string) (string, error) {
+
+	record, err := r.toRecord(pathDelimiter)
+	if err != nil {
+		return "", err
+	}
+
+	return formatCSVRecord(record, delimiter)
+}
+
+// pathResultHeaderRecord returns the fields of the header for the CSV file
+func pathResultHeaderRecord() []string {
+	return []string{
+		"Source entity ID",
+		"Source entity data source",
+		"Destination entity ID",
+		"Destination entity data source",
+		"Number of hops",
+		"Path cost",
+		"Path",
+		"Link",
+		"Documents",
+		"Path mode",
+		"Rank",
+	}
+}
+
+// pathResultHeader returns the header for the delimited file
+func pathResultHeader(delimiter string) (string, error) {
+	return formatCSVRecord(pathResultHeaderRecord(), delimiter)
+}
+
+// Labels used for a pair of entities that doesn't have labels
+const (
+	defaultSourceLabel      = "source"
+	defaultDestinationLabel = "destination"
+)
+
+// EntityPair represents a pair of entities to search between
+type EntityPair struct {
+	Source           string // source entity ID
+	Destination      string // destination entity ID
+	SourceLabel      string // label for the source entity (in place of its data source)
+	DestinationLabel string // label for the destination entity (in place of its data source)
+}
+
+// extractEntityPair parses the entity pair, either "source,destination" or
+// "source,destination,source_label,dest_label"
+func extractEntityPair(pair string, delimiter string) (EntityPair, error) {
+
+	// Split the pair, e.g. "e-1,e-2" into entities
+	parts := strings.Split(pair, delimiter)
+
+	if len(parts) != 2 && len(parts) != 4 {
+		return EntityPair{}, fmt.Errorf("%w: expected 2 entity IDs (and optionally 2 labels), got %v fields in %v", ErrInvalidRow, len(parts), pair)
+	}
+
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+
+	if len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return EntityPair{}, fmt.Errorf("%w: empty entity ID in %v", ErrInvalidRow, pair)
+	}
+
+	if isDocumentVertex(parts[0]) || isDocumentVertex(parts[1]) {
+		return EntityPair{}, fmt.Errorf("%w: invalid entity ID in %q", ErrInvalidRow, pair)
+	}
+
+	entityPair := EntityPair{
+		Source:           parts[0],
+		Destination:      parts[1],
+		SourceLabel:      defaultSourceLabel,
+		DestinationLabel: defaultDestinationLabel,
+	}
+
+	if len(parts) == 4 {
+		entityPair.SourceLabel = parts[2]
+		entityPair.DestinationLabel = parts[3]
+	}
+
+	return entityPair, nil
+}
+
+// ReadEntityPairs reads the pairs of entities to search between from a CSV file with one pair per line
+func ReadEntityPairs(filepath string) ([]EntityPair, error) {
+
+	log.Printf("Reading entity pairs from: %v\n", filepath)
+
+	lines, err := ReadFileIntoSlice(filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs := []EntityPair{}
+
+	for i, line := range *lines {
+
+		// Ignore blank lines
+		if len(strings.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		pair, err := extractEntityPair(line, ",")
+		if err != nil {
+			return nil, fmt.Errorf("invalid entity pair on line %v of %v: %w", i+1, filepath, err)
+		}
+
+		pairs = append(pairs, pair)
+	}
+
+	log.Printf("Read %v entity pairs from file %v\n", len(pairs), filepath)
+
+	return pairs, nil
+}
+
+// buildPathResult builds a PathResult for a path found in the graph, including its supporting documents
+func buildPathResult(c *CompactGraph, source string, sourceDataSource string,
+	destination string, destinationDataSource string,
+	path []string, rank int, outputConfig OutputConfig) (PathResult, error) {
+
+	result, err := NewPathResult(source, sourceDataSource,
+		destination, destinationDataSource,
+		path, outputConfig.WebAppLink)
+
+	if err != nil {
+		return PathResult{}, err
+	}
+
+	result.Documents = c.PathDocuments(path)
+	result.Cost = c.PathCost(path)
+	result.Mode = outputConfig.pathMode()
+	result.Rank = rank
+
+	return result, nil
+}
+
+// findPaths finds the paths between the source and destination using the path mode in the config
+func findPaths(ctx context.Context, c *CompactGraph, source string, destination string, outputConfig OutputConfig) ([][]string, error) {
+
+	switch outputConfig.pathMode() {
+
+	case PathModeAllShortest:
+		// Find all the paths with the minimum number of hops up to a maximum length
+		return c.AllShortestPaths(ctx, source, destination, outputConfig.MaxDepth)
+
+	case PathModeAllSimple:
+		// Find all the paths between the source and destination up to a maximum length
+		nodes, err := c.AllPaths(ctx, source, destination, outputConfig.MaxDepth)
+		if err != nil {
+			return nil, err
+		}
+		return flattenAll(nodes), nil
+
+	case PathModeKShortest:
+		// Find the k paths with the fewest hops using Yen's algorithm
+		return c.KShortestPaths(ctx, source, destination, outputConfig.MaxPathsPerPair, outputConfig.MaxDepth)
+	}
+
+	// Compute the shortest path using BFS or the least cost path using Dijkstra's algorithm
+	var found bool
+	var vertex *Vertex
+	var err error
+
+	switch outputConfig.Algorithm {
+	case AlgorithmDijkstra:
+		found, vertex, err = c.Dijkstra(ctx, source, destination, outputConfig.costLimit())
+	case AlgorithmBidirectional:
+		found, vertex, err = c.BidirectionalBfs(ctx, source, destination, outputConfig.MaxDepth)
+	default:
+		found, vertex, err = c.Bfs(ctx, source, destination, outputConfig.MaxDepth)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return [][]string{}, nil
+	}
+
+	return [][]string{vertex.flatten()}, nil
+}
+
+// findPathResults finds the shortest path(s) between the source and destination
+func findPathResults(ctx context.Context, c *CompactGraph,
+	source string, sourceDataSource string,
+	destination string, destinationDataSource string,
+	outputConfig OutputConfig) ([]PathResult, error) {
+
+	paths, err := findPaths(ctx, c, source, destination, outputConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(paths) == 0 {
+		// The destination wasn't checked for reachability, so there may not be a path
+		if !outputConfig.usesReachability() {
+			return []PathResult{}, nil
+		}
+
+		return nil, fmt.Errorf("vertex %v was deemed reachable from %v, but no path", destination, source)
+	}
+
+	results := make([]PathResult, len(paths))
+
+	for i, path := range paths {
+		results[i], err = buildPathResult(c, source, sourceDataSource,
+			destination, destinationDataSource,
+			path, i+1, outputConfig)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+// vertexSet is a set of vertex identifiers that must be released once it's no longer needed
+type vertexSet interface {
+	Has(identifier string) bool
+	Release()
+}
+
+// reachableFrom returns the set of vertices reachable from the source vertex in the search, which
+// must be released once it's no longer needed. If reachability isn't used by the search, then the
+// set is nil.
+func reachableFrom(ctx context.Context, c *CompactGraph, source string, outputConfig OutputConfig) (bool, vertexSet, error) {
+
+	// Bidirectional search doesn't need to explore the whole neighbourhood of the source
+	if !outputConfig.usesReachability() {
+		_, present := c.present(source)
+		return present, nil, nil
+	}
+
+	// Dijkstra's algorithm is limited by cost rather than the number of hops
+	if outputConfig.Algorithm == AlgorithmDijkstra && outputConfig.pathMode() == PathModeFirst {
+		found, reachable, err := c.ReachableWithinCost(ctx, source, outputConfig.costLimit())
+		if err != nil || !found {
+			return found, nil, err
+		}
+		return true, reachable, nil
+	}
+
+	found, reachable, err := c.ReachableVertices(ctx, source, outputConfig.MaxDepth)
+	if err != nil || !found {
+		return found, nil, err
+	}
+	return true, reachable, nil
+}
+
+// totalNumberOfPairs returns the total number of pairs of entities for the pair mode. Each
+// unordered pair within a data source is counted once. In neighbourhood mode, each seed entity
+// counts as one pair.
+func totalNumberOfPairs(dataSources *[]DataSource, pairMode string) int {
+
+	total := 0
+
+	if pairMode == PairModeNeighbourhood {
+		for _, dataSource := range *dataSources {
+			total += len(dataSource.EntityIds)
+		}
+		return total
+	}
+
+	// Walk through each ordered pair of data sources
+	if hasCrossPairs(pairMode) {
+		for i := 0; i < len(*dataSources)-1; i++ {
+			for j := i + 1; j < len(*dataSources); j++ {
+
+				lenA := len((*dataSources)[i].EntityIds)
+				lenB := len((*dataSources)[j].EntityIds)
+
+				total += (lenA * lenB)
+
+			}
+		}
+	}
+
+	// Walk through each data source with pairs within it
+	for i := range *dataSources {
+		if (*dataSources)[i].hasWithinPairs(pairMode) {
+			n := len((*dataSources)[i].EntityIds)
+			total += n * (n - 1) / 2
+		}
+	}
+
+	return total
+}
+
+// Summary represents the statistics from the shortest path analysis
+type Summary struct {
+	TotalPairs     int `json:"total_pairs"`               // total number of entity pairs
+	PairsProcessed int `json:"pairs_processed"`           // number of entity pairs processed
+	PairsWithPaths int `json:"pairs_with_paths"`          // number of entity pairs connected by a path
+	PathsFound     int `json:"paths_found"`               // total number of paths found
+	PairsTimedOut  int `json:"pairs_timed_out,omitempty"` // number of entity pairs whose search ran out of time
+}
+
+// display the summary
+func (s *Summary) display() {
+	log.Printf("Summary - Total number of entity pairs:   %v\n", s.TotalPairs)
+	log.Printf("Summary - Number of pairs with paths:     %v\n", s.PairsWithPaths)
+	log.Printf("Summary - Percentage of pairs with paths: %.2f %%\n", 100.0*float32(s.PairsWithPaths)/float32(s.TotalPairs))
+	log.Printf("Summary - Total number of paths found:    %v\n", s.PathsFound)
+	log.Printf("Summary - Number of pairs timed out:      %v\n", s.PairsTimedOut)
+}
+
+// performBfs performs breadth first search or exhaustive search given a graph and config. If the
+// context is cancelled, then the results found so far are kept and the context's error is returned.
+func performBfs(ctx context.Context, g *Graph, config PathConfig) (Summary, error) {
+
+	entityConfig := config.Entities
+	outputConfig := config.Output
+
+	// Read the pairs of entities before creating the output file
+	var pairs []EntityPair
+	if len(entityConfig.PairsFile) > 0 {
+		var err error
+		if pairs, err = ReadEntityPairs(entityConfig.PairsFile); err != nil {
+			return Summary{}, err
+		}
+	}
+
+	// Read the units completed by earlier runs (if resuming). Otherwise, the checkpoint of an
+	// earlier run is removed so that it can't be resumed after this run.
+	checkpointFile := checkpointFilePath(outputConfig.OutputFile)
+	state := resumeState{completed: map[int]checkpointEntry{}}
+
+	if outputConfig.Resume {
+		var err error
+		if state, err = readCheckpoint(checkpointFile); err != nil {
+			return Summary{}, err
+		}
+		log.Printf("Resuming after %v completed units\n", len(state.completed))
+	} else if err := removeCheckpoint(checkpointFile); err != nil {
+		return Summary{}, err
+	}
+
+	// Split the work by source entity, either for the pairs in the file or the pairs of data sources
+	var units []workUnit
+	summary := state.summary
+
+	if len(entityConfig.PairsFile) > 0 {
+		units = buildPairWorkUnits(pairs)
+		summary.TotalPairs = len(pairs)
+	} else {
+		units = buildWorkUnits(entityConfig)
+		summary.TotalPairs = totalNumberOfPairs(&entityConfig.DataSources, entityConfig.pairMode())
+	}
+
+	// Skip the units completed by earlier runs
+	units, err := state.remaining(units, outputConfig.outputFormat())
+	if err != nil {
+		return Summary{}, err
+	}
+
+	// Open the output file for writing
+	outputFile, err := openOutputFile(outputConfig.OutputFile, state)
+	if err != nil {
+		return Summary{}, err
+	}
+	defer outputFile.Close()
+
+	// Record the units as they're completed, so that the run can be resumed if it's interrupted
+	cp, err := newCheckpoint(checkpointFile, outputFile, outputConfig.outputFormat(), state)
+	if err != nil {
+		return Summary{}, err
+	}
+	defer cp.close()
+
+	// Write the header (if any) to the output file
+	writer, err := newResultWriter(outputFile, outputConfig)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	// Build the compact form of the graph for the searches. The map-based graph isn't used after
+	// this, so its memory can be reclaimed.
+	c := g.Freeze()
+	log.Printf("Compact graph has %v vertices and %v edges\n", c.NumVertices(), c.NumEdges())
+
+	// Gather the paths into a subgraph (if required)
+	var subgraph *Subgraph
+	if len(outputConfig.SubgraphFile) > 0 {
+		subgraph = NewSubgraph()
+		writer = &subgraphWriter{resultWriter: writer, graph: c, subgraph: subgraph}
+	}
+
+	// Keep the paths for the HTML report (if required)
+	var report *reportWriter
+	if outputConfig.HTMLReport {
+		neighbourhood := entityConfig.pairMode() == PairModeNeighbourhood
+		report = &reportWriter{resultWriter: writer, reportBuilder: newReportBuilder(maxReportResults, neighbourhood)}
+		writer = report
+	}
+
+	// Continue after the results of the completed units
+	if state.offset > 0 {
+		err = writer.resume(state.numResults())
+	} else {
+		err = writer.begin()
+	}
+
+	if err != nil {
+		return Summary{}, fmt.Errorf("unable to write to output file %v: %w", outputConfig.OutputFile, err)
+	}
+
+	// Make a set of entities to skip
+	skipEntities := SliceToSet(entityConfig.Skip)
+
+	log.Printf("Performing shortest path analysis on %v vertex pairs\n", summary.TotalPairs-summary.PairsProcessed)
+
+	// Process the units using a pool of workers
+	results := processWorkUnits(ctx, c, units, skipEntities, outputConfig)
+
+	// Write the results from a single goroutine
+	if err := writeUnitResults(results, writer, cp, outputConfig, &summary); err != nil {
+		return summary, err
+	}
+
+	// Units may not have been started if the run was cancelled
+	if err := ctx.Err(); err != nil {
+		return summary, err
+	}
+
+	// Write the remaining checkpoint entries
+	if err := cp.close(); err != nil {
+		return summary, err
+	}
+
+	// Write the summary (if required by the format)
+	if err := writer.end(summary); err != nil {
+		return summary, fmt.Errorf("unable to write to output file %v: %w", outputConfig.OutputFile, err)
+	}
+
+	// Write the subgraph of the paths found
+	if subgraph != nil {
+		log.Printf("Writing subgraph of %v vertices to file: %v\n", len(subgraph.Roles), outputConfig.SubgraphFile)
+		if err := subgraph.WriteFile(outputConfig.SubgraphFile, outputConfig.subgraphFormat()); err != nil {
+			return summary, err
+		}
+	}
+
+	// Write the HTML report of the paths found
+	if report != nil {
+		reportFile := reportFilePath(outputConfig.OutputFile)
+		log.Printf("Writing HTML report to file: %v\n", reportFile)
+		if err := writeHTMLReportFile(reportFile, report.build(config, summary)); err != nil {
+			return summary, err
+		}
+	}
+
+	// The run is complete, so there's nothing to resume
+	if err := removeCheckpoint(checkpointFile); err != nil {
+		return summary, err
+	}
+
+	summary.display()
+
+	return summary, nil
+}
+
+// buildGraph reads the entity-document relationships from the input files and builds the
+// weighted unipartite graph, adding the edges read directly from the edge lists
+func buildGraph(config PathConfig) (*Graph, error) {
+
+	// Separate the entity-document files from the edge lists
+	var documentFiles, edgeFiles []InputFile
+	for _, file := range config.InputFiles {
+		if file.inputType() == InputTypeEdges {
+			edgeFiles = append(edgeFiles, file)
+		} else {
+			documentFiles = append(documentFiles, file)
+		}
+	}
+
+	skipEntities := SliceToSet(config.Entities.Skip)
+
+	// Read the entity-document relationships from file
+	log.Println("Reading entity-document graph from file ...")
+	t1 := time.Now()
+	connections, documentWeights, err := ReadInputFiles(documentFiles, skipEntities)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Entity-document graph read in %v\n", time.Now().Sub(t1))
+
+	// Convert the bipartite graph to a unipartite graph
+	t2 := time.Now()
+	graph, err := BipartiteToUnipartite(connections, config.Entities.MaxEntitiesPerDocument, config.Entities.LargeDocumentPolicy)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Bipartite to unipartite conversion completed in %v\n", time.Now().Sub(t2))
+
+	// Add the edges from the edge lists
+	if len(edgeFiles) > 0 {
+		t3 := time.Now()
+		if err := AddEdgeLists(graph, edgeFiles, skipEntities, documentWeights); err != nil {
+			return nil, err
+		}
+		log.Printf("Edge lists read in %v\n", time.Now().Sub(t3))
+	}
+
+	// Calculate the cost of each edge
+	if err := ComputeEdgeWeights(graph, connections, config.Output.EdgeWeight, documentWeights); err != nil {
+		return nil, err
+	}
+
+	return graph, nil
+}
+
+// loadGraph reads the graph from the snapshot file if it was built from the same inputs and
+// settings, otherwise the graph is built and the snapshot is (re)written. Returns true if the
+// graph was read from the snapshot.
+func loadGraph(config PathConfig) (*Graph, bool, error) {
+
+	if len(config.SnapshotFile) == 0 {
+		graph, err := buildGraph(config)
+		return graph, false, err
+	}
+
+	key, err := NewSnapshotKey(config)
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to check graph snapshot: %w", err)
+	}
+
+	t0 := time.Now()
+	graph, err := loadSnapshot(config.SnapshotFile, key)
+	if err == nil {
+		log.Printf("Graph read from snapshot %v in %v\n", config.SnapshotFile, time.Now().Sub(t0))
+		return graph, true, nil
+	}
+
+	log.Printf("Rebuilding graph snapshot: %v\n", err)
+	graph, err = buildGraph(config)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := WriteSnapshot(config.SnapshotFile, graph, key); err != nil {
+		return nil, false, fmt.Errorf("unable to write graph snapshot %v: %w", config.SnapshotFile, err)
+	}
+	log.Printf("Graph snapshot written to %v\n", config.SnapshotFile)
+
+	return graph, false, nil
+}
+
+// Run builds the graph (or loads it from the snapshot) and finds the paths between the pairs of
+// entities in the config. The run stops when the context is cancelled, in which case the summary
+// covers the pairs processed before it stopped.
+func Run(ctx context.Context, config PathConfig) (Summary, error) {
+
+	// Fill in the defaults and check the config (which may not have been read from file)
+	if err := config.Normalize(); err != nil {
+		return Summary{}, err
+	}
+
+	return run(ctx, config)
+}
+
+// run builds the graph (or loads it from the snapshot) and finds the paths between the pairs of
+// entities in a normalized config
+func run(ctx context.Context, config PathConfig) (Summary, error) {
+
+	// Check there are pairs of entities to find connections between
+	if len(config.Entities.PairsFile) == 0 && totalNumberOfPairs(&config.Entities.DataSources, config.Entities.pairMode()) == 0 {
+		return Summary{}, ErrNoPairs
+	}
+
+	if err := ctx.Err(); err != nil {
+		return Summary{}, err
+	}
+
+	// Build the graph (or load it from the snapshot)
+	graph, _, err := loadGraph(config)
+	if err != nil {
+		return Summary{}, err
+	}
+	log.Printf("Graph has %v vertices\n", len(graph.Nodes))
+
+	// Write the unipartite graph to file (if required)
+	if len(config.Output.UnipartiteFile) > 0 {
+		log.Printf("Writing unipartite graph to file: %v\n", config.Output.UnipartiteFile)
+
+		// Each directed edge is written, otherwise each undirected edge is written once
+		write := graph.WriteUndirectedEdgeList
+		if config.hasDirectedEdges() {
+			write = graph.WriteEdgeList
+		}
+
+		if err := write(config.Output.UnipartiteFile, config.Output.PathDelimiter); err != nil {
+			return Summary{}, err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return Summary{}, err
+	}
+
+	// Perform shortest path analysis
+	t0 := time.Now()
+	summary, err := performBfs(ctx, graph, config)
+	if err != nil {
+		return summary, err
+	}
+	log.Printf("Shortest path analysis completed in %v\n", time.Now().Sub(t0))
+
+	return summary, nil
+}
+
+// PerformBfs performs BFS based on a config, logging the config and where the results are located
+func PerformBfs(ctx context.Context, config PathConfig) error {
+
+	t0 := time.Now()
+	if err := config.Normalize(); err != nil {
+		return err
+	}
+	config.display()
+
+	if _, err := run(ctx, config); err != nil {
+		return err
+	}
+
+	// Complete
+	log.Printf("Results located at: %v\n", config.Output.OutputFile)
+	log.Printf("Total time taken: %v\n", time.Now().Sub(t0))
+
+	return nil
+}
+
+// PerformBfsFromConfig performs BFS based on a config file
+func PerformBfsFromConfig(ctx context.Context, configFilepath string) error {
+
+	// Read the JSON configuration
+	log.Println("Reading configuration ...")
+	config, err := ReadConfig(configFilepath)
+	if err != nil {
+		return err
+	}
+
+	return PerformBfs(ctx, config)
+}
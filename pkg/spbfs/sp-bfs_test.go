@@ -0,0 +1,1096 @@
+package spbfs
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadConfig(t *testing.T) {
+	actual, err := ReadConfig("./test/test-data/test-config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := PathConfig{
+		InputFiles: []InputFile{
+			{Path: "./test/test-data/entity_1.csv", Weight: 1.0},
+			{Path: "./test/test-data/entity_2.csv", Weight: 1.0},
+			{Path: "./test/test-data/entity_3.csv", Weight: 1.0},
+		},
+		Entities: EntityConfig{
+			DataSources: []DataSource{
+				{
+					Name:      "set-1",
+					EntityIds: []string{"e-1", "e-2"},
+				},
+				{
+					Name:      "set-2",
+					EntityIds: []string{"e-5", "e-6"},
+				},
+			},
+			Skip: []string{},
+		},
+		Output: OutputConfig{
+			MaxDepth:        3,
+			Algorithm:       AlgorithmBfs,
+			EdgeWeight:      WeightUnit,
+			OutputFile:      "./test/test-data/results.csv",
+			OutputDelimiter: ",",
+			PathDelimiter:   "|",
+			WebAppLink:      "http://192.168.99.100:8080/show/<ENTITY_IDS>",
+		},
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestReadConfigInvalid(t *testing.T) {
+	filepaths := []string{
+		"./test/test-data/missing-config.json",
+		"./test/test-data/entity_1.csv",
+		"./test/test-data/test-config-invalid.json",
+	}
+
+	for _, filepath := range filepaths {
+		if _, err := ReadConfig(filepath); !errors.Is(err, ErrConfig) {
+			t.Errorf("%v: expected %v, got %v\n", filepath, ErrConfig, err)
+		}
+	}
+}
+
+func TestPathConfigValidate(t *testing.T) {
+	config, err := ReadConfig("./test/test-data/test-config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		description string
+		update      func(c *PathConfig)
+	}{
+		{"algorithm", func(c *PathConfig) { c.Output.Algorithm = "unknown" }},
+		{"edge weight", func(c *PathConfig) { c.Output.EdgeWeight = "unknown" }},
+		{"max depth", func(c *PathConfig) { c.Output.MaxDepth = -1 }},
+		{"max cost", func(c *PathConfig) { c.Output.MaxCost = -1 }},
+		{"path mode", func(c *PathConfig) { c.Output.PathMode = "unknown" }},
+		{"k shortest", func(c *PathConfig) { c.Output.PathMode = PathModeKShortest }},
+		{"dijkstra with all paths", func(c *PathConfig) {
+			c.Output.Algorithm = AlgorithmDijkstra
+			c.Output.PathMode = PathModeAllSimple
+		}},
+		{"dijkstra with k shortest", func(c *PathConfig) {
+			c.Output.Algorithm = AlgorithmDijkstra
+			c.Output.PathMode = PathModeKShortest
+			c.Output.MaxPathsPerPair = 2
+		}},
+		{"max cost with all shortest", func(c *PathConfig) {
+			c.Output.MaxCost = 2
+			c.Output.PathMode = PathModeAllShortest
+		}},
+		{"output format", func(c *PathConfig) { c.Output.OutputFormat = "unknown" }},
+		{"delimiter", func(c *PathConfig) { c.Output.OutputDelimiter = "\"" }},
+		{"path delimiter", func(c *PathConfig) { c.Output.PathDelimiter = "" }},
+		{"subgraph format", func(c *PathConfig) {
+			c.Output.SubgraphFile = "subgraph.txt"
+			c.Output.SubgraphFormat = "unknown"
+		}},
+		{"pair mode", func(c *PathConfig) { c.Entities.PairMode = "unknown" }},
+		{"max entities", func(c *PathConfig) { c.Entities.MaxEntitiesPerDocument = -1 }},
+		{"large document policy", func(c *PathConfig) { c.Entities.LargeDocumentPolicy = "unknown" }},
+		{"min depth", func(c *PathConfig) { c.Output.MinDepth = 4 }},
+		{"max results", func(c *PathConfig) { c.Output.MaxResultsPerSeed = -1 }},
+		{"pair timeout", func(c *PathConfig) { c.Output.PairTimeout = "soon" }},
+		{"negative pair timeout", func(c *PathConfig) { c.Output.PairTimeout = "-1s" }},
+		{"resume with subgraph", func(c *PathConfig) {
+			c.Output.Resume = true
+			c.Output.SubgraphFile = "subgraph.graphml"
+		}},
+		{"resume with report", func(c *PathConfig) {
+			c.Output.Resume = true
+			c.Output.HTMLReport = true
+		}},
+		{"input file path", func(c *PathConfig) { c.InputFiles = []InputFile{{}} }},
+		{"input file delimiter", func(c *PathConfig) { c.InputFiles = []InputFile{{Path: "a.csv", Delimiter: ";;"}} }},
+		{"input file comment", func(c *PathConfig) { c.InputFiles = []InputFile{{Path: "a.csv", Comment: ","}} }},
+		{"input file column", func(c *PathConfig) { c.InputFiles = []InputFile{{Path: "a.csv", EntityColumn: "entity"}} }},
+		{"input file same columns", func(c *PathConfig) {
+			c.InputFiles = []InputFile{{Path: "a.csv", EntityColumn: "id", DocumentColumn: "id"}}
+		}},
+		{"input type", func(c *PathConfig) { c.InputFiles = []InputFile{{Path: "a.csv", InputType: "unknown"}} }},
+		{"edge list columns", func(c *PathConfig) {
+			c.InputFiles = []InputFile{{Path: "a.csv", InputType: InputTypeEdges, EntityColumn: "a", DocumentColumn: "b"}}
+		}},
+		{"directed entity-document file", func(c *PathConfig) { c.InputFiles = []InputFile{{Path: "a.csv", Directed: true}} }},
+		{"bidirectional with directed edges", func(c *PathConfig) {
+			c.InputFiles = []InputFile{{Path: "a.csv", InputType: InputTypeEdges, Directed: true}}
+			c.Output.Algorithm = AlgorithmBidirectional
+		}},
+		{"input files from stdin", func(c *PathConfig) {
+			c.InputFiles = []InputFile{{Path: StdinPath}, {Path: StdinPath}}
+		}},
+		{"input file from stdin with snapshot", func(c *PathConfig) {
+			c.InputFiles = []InputFile{{Path: StdinPath}}
+			c.SnapshotFile = "graph.snapshot"
+		}},
+		{"input file columns without header", func(c *PathConfig) {
+			hasHeader := false
+			c.InputFiles = []InputFile{{Path: "a.csv", EntityColumn: "entity", DocumentColumn: "doc", HasHeader: &hasHeader}}
+		}},
+	}
+
+	for _, testCase := range testCases {
+		invalid := config
+		testCase.update(&invalid)
+
+		if err := invalid.validate(); !errors.Is(err, ErrConfig) {
+			t.Errorf("%v: expected %v, got %v\n", testCase.description, ErrConfig, err)
+		}
+	}
+}
+
+func TestPathConfigValidateDelimiters(t *testing.T) {
+	config, err := ReadConfig("./test/test-data/test-config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		description string
+		update      func(c *PathConfig)
+	}{
+		{"output delimiter", func(c *PathConfig) { c.Output.OutputDelimiter = ";;" }},
+		{"output delimiter quote", func(c *PathConfig) { c.Output.OutputDelimiter = "\"" }},
+		{"unipartite path delimiter", func(c *PathConfig) {
+			c.Output.UnipartiteFile = "unipartite.csv"
+			c.Output.PathDelimiter = "\n"
+		}},
+		{"input file delimiter", func(c *PathConfig) { c.InputFiles = []InputFile{{Path: "a.csv", Delimiter: ";;"}} }},
+		{"input file comment", func(c *PathConfig) { c.InputFiles = []InputFile{{Path: "a.csv", Comment: "##"}} }},
+	}
+
+	for _, testCase := range testCases {
+		invalid := config
+		testCase.update(&invalid)
+
+		if err := invalid.validate(); !errors.Is(err, ErrConfig) || !errors.Is(err, ErrInvalidDelimiter) {
+			t.Errorf("%v: expected %v and %v, got %v\n", testCase.description, ErrConfig, ErrInvalidDelimiter, err)
+		}
+	}
+}
+
+func TestPathConfigValidateWeightedSearch(t *testing.T) {
+	config, err := ReadConfig("./test/test-data/test-config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Dijkstra's algorithm and the maximum cost are only used for the first path
+	for _, mode := range []string{PathModeAllShortest, PathModeAllSimple, PathModeKShortest} {
+		weighted := config
+		weighted.Output.PathMode = mode
+		weighted.Output.MaxPathsPerPair = 2
+		weighted.Output.Algorithm = AlgorithmDijkstra
+
+		if err := weighted.validate(); !errors.Is(err, ErrConfig) || !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("%v: expected %v and %v, got %v\n", mode, ErrConfig, ErrInvalidArgument, err)
+		}
+
+		weighted.Output.Algorithm = AlgorithmBfs
+		weighted.Output.MaxCost = 2.5
+
+		if err := weighted.validate(); !errors.Is(err, ErrConfig) || !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("%v: expected %v and %v, got %v\n", mode, ErrConfig, ErrInvalidArgument, err)
+		}
+	}
+
+	config.Output.PathMode = PathModeFirst
+	config.Output.Algorithm = AlgorithmDijkstra
+	config.Output.MaxCost = 2.5
+
+	if err := config.validate(); err != nil {
+		t.Errorf("Didn't expect an error, got %v\n", err)
+	}
+}
+
+func TestInputFileUnmarshalJSON(t *testing.T) {
+	var files []InputFile
+	err := json.Unmarshal([]byte(`["a.csv", {"path": "b.csv", "weight": 2.5}, {"path": "c.csv"},
+		{"path": "d.tsv", "delimiter": "\t", "entity_column": "entity", "document_column": "doc_ref",
+		 "has_header": true, "comment": "#"}]`), &files)
+
+	if err != nil {
+		t.Fatalf("Didn't expect an error, got: %v\n", err)
+	}
+
+	hasHeader := true
+	expected := []InputFile{
+		{Path: "a.csv", Weight: 1.0},
+		{Path: "b.csv", Weight: 2.5},
+		{Path: "c.csv", Weight: 1.0},
+		{Path: "d.tsv", Weight: 1.0, Delimiter: "\t", EntityColumn: "entity", DocumentColumn: "doc_ref",
+			HasHeader: &hasHeader, Comment: "#"},
+	}
+
+	if !reflect.DeepEqual(expected, files) {
+		t.Fatalf("Expected %v, got %v\n", expected, files)
+	}
+}
+
+// testPathResult builds a path result without a web app link, failing the test on an error
+func testPathResult(t *testing.T, source string, sourceDataSource string,
+	destination string, destinationDataSource string, vertices []string) PathResult {
+
+	t.Helper()
+	result, err := NewPathResult(source, sourceDataSource, destination, destinationDataSource, vertices, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return result
+}
+
+func TestNewPathResult(t *testing.T) {
+	actual, err := NewPathResult("e-1", "set-1", "e-3", "set-2", []string{"e-1", "e-20", "e-3"}, "http://localhost/show.php?<ENTITY_IDS>&v")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := PathResult{
+		SourceEntityID:              "e-1",
+		SourceEntityDataSource:      "set-1",
+		DestinationEntityID:         "e-3",
+		DestinationEntityDataSource: "set-2",
+		NumberOfHops:                2,
+		Cost:                        2,
+		Rank:                        1,
+		Path:                        []string{"e-1", "e-20", "e-3"},
+		WebAppLink:                  "http://localhost/show.php?e-1,e-20,e-3&v",
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestNewPathResultInvalid(t *testing.T) {
+	testCases := []struct {
+		source                string
+		sourceDataSource      string
+		destination           string
+		destinationDataSource string
+		vertices              []string
+		expected              error
+	}{
+		{"", "set-1", "e-2", "set-2", []string{"e-1", "e-2"}, ErrEmptyVertex},
+		{"e-1", "", "e-2", "set-2", []string{"e-1", "e-2"}, ErrInvalidArgument},
+		{"e-1", "set-1", "", "set-2", []string{"e-1", "e-2"}, ErrEmptyVertex},
+		{"e-1", "set-1", "e-2", "", []string{"e-1", "e-2"}, ErrInvalidArgument},
+		{"e-1", "set-1", "e-2", "set-2", []string{"e-1"}, ErrEmptyPath},
+		{"e-1", "set-1", "e-2", "set-2", []string{}, ErrEmptyPath},
+	}
+
+	for _, testCase := range testCases {
+		_, err := NewPathResult(testCase.source, testCase.sourceDataSource, testCase.destination,
+			testCase.destinationDataSource, testCase.vertices, "")
+
+		if !errors.Is(err, testCase.expected) {
+			t.Errorf("%v: expected %v, got %v\n", testCase, testCase.expected, err)
+		}
+	}
+}
+
+func TestPathResultDisplay(t *testing.T) {
+	pathResult, err := NewPathResult("e-1", "set-1", "e-3", "set-2", []string{"e-1", "e-20", "e-3"}, "http://localhost/show.php?<ENTITY_IDS>&v")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual := pathResult.display()
+	expected := "e-1:set-1 -> e-3:set-2 (2 hops) [e-1 e-20 e-3]"
+
+	if expected != actual {
+		t.Fatalf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestPathResultToStringBlankPathDelimiter(t *testing.T) {
+	pathResult := testPathResult(t, "e-1", "set-1", "e-3", "set-2", []string{"e-1", "e-20", "e-3"})
+
+	if _, err := pathResult.toString(",", ""); !errors.Is(err, ErrInvalidDelimiter) {
+		t.Fatalf("Expected %v, got %v\n", ErrInvalidDelimiter, err)
+	}
+}
+
+func TestPathResultToString(t *testing.T) {
+	pathResult, err := NewPathResult("e-1", "set-1", "e-3", "set-2", []string{"e-1", "e-20", "e-3"}, "http://localhost/show.php?<ENTITY_IDS>&v")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := pathResult.toString(",", "|")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "e-1,set-1,e-3,set-2,2,2,e-1|e-20|e-3,\"http://localhost/show.php?e-1,e-20,e-3&v\",,,1"
+
+	if expected != actual {
+		t.Fatalf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestPathResultToStringWithDocuments(t *testing.T) {
+	pathResult, err := NewPathResult("e-1", "set-1", "e-3", "set-2", []string{"e-1", "e-20", "e-3"}, "http://localhost/show.php?<ENTITY_IDS>&v")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pathResult.Documents = [][]string{{"d-100", "d-200"}, {"d-300"}}
+
+	actual, err := pathResult.toString(",", "|")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "e-1,set-1,e-3,set-2,2,2,e-1|e-20|e-3,\"http://localhost/show.php?e-1,e-20,e-3&v\",d-100;d-200|d-300,,1"
+
+	if expected != actual {
+		t.Fatalf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestPathResultToStringQuoting(t *testing.T) {
+	pathResult, err := NewPathResult("e,1", "set \"1\"", "e-3", "set-2", []string{"e,1", "e-3"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := pathResult.toString(",", "|")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "\"e,1\",\"set \"\"1\"\"\",e-3,set-2,1,1,\"e,1|e-3\",,,,1"
+
+	if expected != actual {
+		t.Fatalf("Expected %v, got %v\n", expected, actual)
+	}
+
+	// The comma doesn't need quoting with a tab delimiter
+	actual, err = pathResult.toString("\t", "|")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected = "e,1\t\"set \"\"1\"\"\"\te-3\tset-2\t1\t1\te,1|e-3\t\t\t\t1"
+
+	if expected != actual {
+		t.Fatalf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestExpectedResultsParse(t *testing.T) {
+	files, err := filepath.Glob("./test/*/expected_results*.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reader := csv.NewReader(f)
+		reader.FieldsPerRecord = len(pathResultHeaderRecord())
+
+		if _, err := reader.ReadAll(); err != nil {
+			t.Errorf("%v: %v\n", file, err)
+		}
+
+		f.Close()
+	}
+}
+
+func TestPathResultHeader(t *testing.T) {
+	actual, err := pathResultHeader(",")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "Source entity ID,Source entity data source,Destination entity ID,Destination entity data source,Number of hops,Path cost,Path,Link,Documents,Path mode,Rank"
+
+	if expected != actual {
+		t.Fatalf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestExtractEntityPairValid(t *testing.T) {
+	pair, err := extractEntityPair("e-1|e-4", "|")
+
+	if err != nil {
+		t.Fatalf("Didn't expect an error, got: %v\n", err)
+	}
+
+	expected := EntityPair{
+		Source:           "e-1",
+		Destination:      "e-4",
+		SourceLabel:      defaultSourceLabel,
+		DestinationLabel: defaultDestinationLabel,
+	}
+
+	if pair != expected {
+		t.Fatalf("Entities are not as expected\n")
+	}
+}
+
+func TestExtractEntityPairWithLabels(t *testing.T) {
+	pair, err := extractEntityPair("e-1,e-4,suspects,victims", ",")
+
+	if err != nil {
+		t.Fatalf("Didn't expect an error, got: %v\n", err)
+	}
+
+	expected := EntityPair{
+		Source:           "e-1",
+		Destination:      "e-4",
+		SourceLabel:      "suspects",
+		DestinationLabel: "victims",
+	}
+
+	if pair != expected {
+		t.Fatalf("Expected %v, got %v\n", expected, pair)
+	}
+}
+
+func TestExtractEntityPairInvalid1(t *testing.T) {
+	_, err := extractEntityPair("e-1", "|")
+
+	if err == nil {
+		t.Fatalf("Expected an error\n")
+	}
+}
+
+func TestExtractEntityPairInvalid2(t *testing.T) {
+	_, err := extractEntityPair("e-1|e-3|e-4", "|")
+
+	if err == nil {
+		t.Fatalf("Expected an error\n")
+	}
+}
+
+func TestExtractEntityPairInvalid3(t *testing.T) {
+	_, err := extractEntityPair(",e-3", ",")
+
+	if err == nil {
+		t.Fatalf("Expected an error\n")
+	}
+}
+
+func TestExtractEntityPairDocumentVertex(t *testing.T) {
+	_, err := extractEntityPair("e-1,"+documentVertex("d-1"), ",")
+
+	if !errors.Is(err, ErrInvalidRow) {
+		t.Fatalf("Expected %v, got %v\n", ErrInvalidRow, err)
+	}
+}
+
+func TestBuildWebAppLink(t *testing.T) {
+
+	template := "http://192.168.99.100:8080/show.php?<ENTITY_IDS>&v"
+	entityIds := []string{"e-1", "e-2"}
+	actual, err := buildWebAppLink(template, entityIds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "http://192.168.99.100:8080/show.php?e-1,e-2&v"
+
+	if expected != actual {
+		t.Fatalf("Expected URL: %v, got %v\n", expected, actual)
+	}
+}
+
+func TestBuildWebAppLinkEmptyPath(t *testing.T) {
+	if _, err := buildWebAppLink("http://localhost/show.php?<ENTITY_IDS>", []string{}); !errors.Is(err, ErrEmptyPath) {
+		t.Fatalf("Expected %v, got %v\n", ErrEmptyPath, err)
+	}
+}
+
+func TestBuildPathResultEmptyPath(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("e-1", "e-2")
+
+	for _, path := range [][]string{{}, {"e-1"}} {
+		_, err := buildPathResult(g.Freeze(), "e-1", "set-1", "e-2", "set-2", path, 1, OutputConfig{})
+
+		if !errors.Is(err, ErrEmptyPath) {
+			t.Errorf("%v: expected %v, got %v\n", path, ErrEmptyPath, err)
+		}
+	}
+}
+
+func TestPerformBfs(t *testing.T) {
+
+	// Build a graph with 19 vertices
+	graph := NewGraph()
+
+	// First connected component
+	graph.AddUndirected("e-1", "e-2")
+
+	// Second connected component
+	graph.AddUndirected("e-3", "e-4")
+	graph.AddUndirected("e-4", "e-5")
+	graph.AddUndirected("e-4", "e-6")
+
+	graph.AddUndirected("e-3", "e-8")
+	graph.AddUndirected("e-8", "e-11")
+	graph.AddUndirected("e-3", "e-9")
+	graph.AddUndirected("e-9", "e-11")
+	graph.AddUndirected("e-11", "e-13")
+
+	graph.AddUndirected("e-3", "e-7")
+	graph.AddUndirected("e-7", "e-10")
+	graph.AddUndirected("e-10", "e-12")
+
+	graph.AddUndirected("e-3", "e-14")
+	graph.AddUndirected("e-3", "e-15")
+	graph.AddUndirected("e-3", "e-16")
+	graph.AddUndirected("e-14", "e-17")
+	graph.AddUndirected("e-15", "e-17")
+	graph.AddUndirected("e-16", "e-17")
+	graph.AddUndirected("e-17", "e-18")
+	graph.AddUndirected("e-18", "e-19")
+
+	// Define entity pairs config
+	entityConfig := EntityConfig{
+		DataSources: []DataSource{
+			{
+				Name: "set-1",
+				EntityIds: []string{
+					"e-1",
+					"e-2",
+					"e-3",
+					"e-6",
+					"e-8"},
+			},
+			{
+				Name: "set-2",
+				EntityIds: []string{
+					"e-11",
+					"e-12",
+					"e-13",
+					"e-15",
+					"e-16",
+					"e-17",
+					"e-18",
+					"e-19",
+					"e-100"},
+			},
+		},
+		Skip: []string{},
+	}
+
+	// Define output config
+	outputConfig := OutputConfig{
+		MaxDepth:        3,
+		OutputFile:      "./test/test-data/results.csv",
+		OutputDelimiter: ",",
+		PathDelimiter:   "|",
+		WebAppLink:      "http://192.168.99.100:8080/show/<ENTITY_IDS>",
+	}
+
+	// Run BFS
+	performBfs(context.Background(), &graph, PathConfig{Entities: entityConfig, Output: outputConfig})
+
+	// Check the result
+	if !FilesHaveSameContent("./test/test-data/expected_results.csv", "./test/test-data/results.csv") {
+		t.Fatal("Actual results differ from expected results")
+	}
+}
+
+func TestPerformBfsFromConfig(t *testing.T) {
+
+	// Perform BFS using bipartite data
+	if err := PerformBfsFromConfig(context.Background(), "./test/test-data-full/config.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the result
+	if !FilesHaveSameContent("./test/test-data-full/expected_results.csv", "./test/test-data-full/results.csv") {
+		t.Fatal("Actual results differ from expected results")
+	}
+}
+
+func TestPerformBfsFromConfigErrors(t *testing.T) {
+	if err := PerformBfsFromConfig(context.Background(), "./test/test-data/test-config-no-pairs.json"); !errors.Is(err, ErrNoPairs) {
+		t.Errorf("Expected %v, got %v\n", ErrNoPairs, err)
+	}
+
+	if err := PerformBfsFromConfig(context.Background(), "./test/test-data/test-config-invalid.json"); !errors.Is(err, ErrConfig) {
+		t.Errorf("Expected %v, got %v\n", ErrConfig, err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	config, err := ReadConfig("./test/test-data-full/config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Output.OutputFile = filepath.Join(t.TempDir(), "results.csv")
+
+	summary, err := Run(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.PairsWithPaths != 14 {
+		t.Errorf("Expected 14 pairs with paths, got %v\n", summary.PairsWithPaths)
+	}
+
+	if !FilesHaveSameContent("./test/test-data-full/expected_results.csv", config.Output.OutputFile) {
+		t.Fatal("Actual results differ from expected results")
+	}
+
+	// A cancelled context stops the run before the graph is built
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := Run(ctx, config); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected %v, got %v\n", context.Canceled, err)
+	}
+
+	// A run cancelled part way through returns the summary of the pairs processed
+	config.Output.Workers = 1
+	summary, err = Run(&countdownContext{Context: context.Background(), remaining: 100}, config)
+
+	if !errors.Is(err, context.Canceled) || summary.PairsProcessed == 0 || summary.PairsProcessed == 45 {
+		t.Errorf("Expected a partial summary and %v, got %v and %v\n", context.Canceled, summary, err)
+	}
+}
+
+func TestRunHandBuiltConfig(t *testing.T) {
+
+	// The algorithm and edge weight scheme are left to their defaults
+	config := PathConfig{
+		InputFiles: []InputFile{{Path: "./test/test-data-full/entity_doc_*.csv", Weight: 1.0}},
+		Entities: EntityConfig{
+			DataSources: []DataSource{
+				{Name: "set-1", EntityIds: []string{"e-1", "e-2", "e-3", "e-6", "e-8"}},
+				{Name: "set-2", EntityIds: []string{"e-11", "e-12", "e-13", "e-15", "e-16", "e-17", "e-18", "e-19", "e-100"}},
+			},
+		},
+		Output: OutputConfig{
+			MaxDepth:        3,
+			OutputFile:      filepath.Join(t.TempDir(), "results.csv"),
+			OutputDelimiter: ",",
+			PathDelimiter:   "|",
+			WebAppLink:      "http://192.168.99.100:8080/show/<ENTITY_IDS>",
+		},
+	}
+
+	if _, err := Run(context.Background(), config); err != nil {
+		t.Fatal(err)
+	}
+
+	if !FilesHaveSameContent("./test/test-data-full/expected_results.csv", config.Output.OutputFile) {
+		t.Fatal("Actual results differ from expected results")
+	}
+
+	// An invalid option is reported before the graph is built
+	config.Output.MaxDepth = -1
+	if _, err := Run(context.Background(), config); !errors.Is(err, ErrConfig) {
+		t.Errorf("Expected %v, got %v\n", ErrConfig, err)
+	}
+}
+
+func TestPerformBfsFromConfigThreeDataSources(t *testing.T) {
+
+	// Perform BFS using bipartite data
+	if err := PerformBfsFromConfig(context.Background(), "./test/test-data-full/config-2.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the result
+	if !FilesHaveSameContent("./test/test-data-full/expected_results-2.csv", "./test/test-data-full/results-2.csv") {
+		t.Fatal("Actual results differ from expected results")
+	}
+}
+
+func TestPerformBfsFromConfigWithSkips(t *testing.T) {
+
+	// Perform BFS using bipartite data
+	if err := PerformBfsFromConfig(context.Background(), "./test/test-data-full-2/config.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the result
+	if !FilesHaveSameContent("./test/test-data-full-2/expected_results.csv", "./test/test-data-full-2/results.csv") {
+		t.Fatal("Actual results differ from expected results")
+	}
+}
+
+func TestPerformFindAllShortestPathsFromConfig(t *testing.T) {
+
+	// Perform BFS using bipartite data
+	if err := PerformBfsFromConfig(context.Background(), "./test/test-data-full-3/config.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the result
+	if !FilesHaveSameContent("./test/test-data-full-3/expected_results.csv", "./test/test-data-full-3/results.csv") {
+		t.Fatal("Actual results differ from expected results")
+	}
+}
+
+func TestPerformDijkstraFromConfig(t *testing.T) {
+
+	// Perform Dijkstra's algorithm using bipartite data with file weights
+	if err := PerformBfsFromConfig(context.Background(), "./test/test-data-full/config-dijkstra.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the result
+	if !FilesHaveSameContent("./test/test-data-full/expected_results-dijkstra.csv", "./test/test-data-full/results-dijkstra.csv") {
+		t.Fatal("Actual results differ from expected results")
+	}
+}
+
+func TestPerformBidirectionalBfsFromConfig(t *testing.T) {
+
+	// Perform bidirectional BFS using bipartite data
+	if err := PerformBfsFromConfig(context.Background(), "./test/test-data-full/config-bidirectional.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the result
+	if !FilesHaveSameContent("./test/test-data-full/expected_results.csv", "./test/test-data-full/results-bidirectional.csv") {
+		t.Fatal("Actual results differ from expected results")
+	}
+}
+
+func TestPerformAllShortestPathsFromConfig(t *testing.T) {
+
+	// Find all shortest paths using bipartite data
+	if err := PerformBfsFromConfig(context.Background(), "./test/test-data-full/config-all-shortest.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the result
+	if !FilesHaveSameContent("./test/test-data-full/expected_results-all-shortest.csv", "./test/test-data-full/results-all-shortest.csv") {
+		t.Fatal("Actual results differ from expected results")
+	}
+}
+
+func TestPerformAllSimplePathsFromConfig(t *testing.T) {
+
+	// Find all simple paths using bipartite data
+	if err := PerformBfsFromConfig(context.Background(), "./test/test-data-full/config-all-simple.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the result
+	if !FilesHaveSameContent("./test/test-data-full/expected_results-all-simple.csv", "./test/test-data-full/results-all-simple.csv") {
+		t.Fatal("Actual results differ from expected results")
+	}
+}
+
+func TestPerformKShortestPathsFromConfig(t *testing.T) {
+
+	// Find the k shortest paths using bipartite data
+	if err := PerformBfsFromConfig(context.Background(), "./test/test-data-full/config-k-shortest.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the result
+	if !FilesHaveSameContent("./test/test-data-full/expected_results-k-shortest.csv", "./test/test-data-full/results-k-shortest.csv") {
+		t.Fatal("Actual results differ from expected results")
+	}
+}
+
+func TestPathMode(t *testing.T) {
+	config := OutputConfig{}
+	if config.pathMode() != PathModeFirst {
+		t.Errorf("Expected %v, got %v\n", PathModeFirst, config.pathMode())
+	}
+
+	config.FindAllPaths = true
+	if config.pathMode() != PathModeAllShortest {
+		t.Errorf("Expected %v, got %v\n", PathModeAllShortest, config.pathMode())
+	}
+
+	config.MaxPathsPerPair = 3
+	if config.pathMode() != PathModeKShortest {
+		t.Errorf("Expected %v, got %v\n", PathModeKShortest, config.pathMode())
+	}
+
+	config.PathMode = PathModeAllSimple
+	if config.pathMode() != PathModeAllSimple {
+		t.Errorf("Expected %v, got %v\n", PathModeAllSimple, config.pathMode())
+	}
+}
+
+func TestTotalNumberOfPairsOneDataset(t *testing.T) {
+	set := []DataSource{
+		{
+			Name:      "set-1",
+			EntityIds: []string{"e-1"},
+		},
+	}
+
+	actual := totalNumberOfPairs(&set, PairModeCross)
+
+	if actual != 0 {
+		t.Errorf("Expected 0 pairs, got %v\n", actual)
+	}
+}
+
+func TestTotalNumberOfPairsTwoDatasets(t *testing.T) {
+	set := []DataSource{
+		{
+			Name:      "set-1",
+			EntityIds: []string{"e-1", "e-2", "e-3"},
+		},
+		{
+			Name:      "set-2",
+			EntityIds: []string{"e-1", "e-3"},
+		},
+	}
+
+	actual := totalNumberOfPairs(&set, PairModeCross)
+
+	if actual != 6 {
+		t.Errorf("Expected 6 pairs, got %v\n", actual)
+	}
+}
+
+func TestTotalNumberOfPairsThreeDatasets(t *testing.T) {
+	set := []DataSource{
+		{
+			Name:      "set-1",
+			EntityIds: []string{"e-1", "e-2", "e-3"},
+		},
+		{
+			Name:      "set-2",
+			EntityIds: []string{"e-1", "e-3"},
+		},
+		{
+			Name:      "set-3",
+			EntityIds: []string{"e-1"},
+		},
+	}
+
+	actual := totalNumberOfPairs(&set, PairModeCross)
+
+	// set-1 and set-2 = 6
+	// set-1 and set-3 = 3
+	// set-2 and set-3 = 2
+	// therefore expected total = 11
+
+	if actual != 11 {
+		t.Errorf("Expected 11 pairs, got %v\n", actual)
+	}
+}
+
+func TestPerformBfsFromConfigWithPairsFile(t *testing.T) {
+
+	// Perform BFS for the pairs of entities in the file
+	if err := PerformBfsFromConfig(context.Background(), "./test/test-data-full/config-pairs.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the result
+	if !FilesHaveSameContent("./test/test-data-full/expected_results-pairs.csv", "./test/test-data-full/results-pairs.csv") {
+		t.Fatal("Actual results differ from expected results")
+	}
+}
+
+func TestTotalNumberOfPairsWithin(t *testing.T) {
+	set := []DataSource{
+		{
+			Name:      "set-1",
+			EntityIds: []string{"e-1", "e-2", "e-3", "e-4"},
+		},
+		{
+			Name:      "set-2",
+			EntityIds: []string{"e-5", "e-6"},
+		},
+	}
+
+	testCases := []struct {
+		pairMode string
+		expected int
+	}{
+		{PairModeCross, 8},
+		{PairModeWithin, 6 + 1},
+		{PairModeAll, 8 + 6 + 1},
+	}
+
+	for _, testCase := range testCases {
+		actual := totalNumberOfPairs(&set, testCase.pairMode)
+
+		if actual != testCase.expected {
+			t.Errorf("Pair mode %v: expected %v pairs, got %v\n", testCase.pairMode, testCase.expected, actual)
+		}
+	}
+}
+
+func TestTotalNumberOfPairsSelfPairs(t *testing.T) {
+	set := []DataSource{
+		{
+			Name:      "set-1",
+			EntityIds: []string{"e-1", "e-2", "e-3"},
+			SelfPairs: true,
+		},
+		{
+			Name:      "set-2",
+			EntityIds: []string{"e-5", "e-6"},
+		},
+	}
+
+	actual := totalNumberOfPairs(&set, PairModeCross)
+
+	// set-1 and set-2 = 6
+	// within set-1 = 3
+	if actual != 9 {
+		t.Errorf("Expected 9 pairs, got %v\n", actual)
+	}
+}
+
+func TestPerformBfsFromConfigWithinDataSource(t *testing.T) {
+
+	// Perform BFS for the pairs across the data sources and within set-1
+	if err := PerformBfsFromConfig(context.Background(), "./test/test-data-full/config-within.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the result
+	if !FilesHaveSameContent("./test/test-data-full/expected_results-within.csv", "./test/test-data-full/results-within.csv") {
+		t.Fatal("Actual results differ from expected results")
+	}
+}
+
+func TestPerformNeighbourhoodFromConfig(t *testing.T) {
+
+	// Find the entities within reach of each seed entity
+	if err := PerformBfsFromConfig(context.Background(), "./test/test-data-full/config-neighbourhood.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the result
+	if !FilesHaveSameContent("./test/test-data-full/expected_results-neighbourhood.csv", "./test/test-data-full/results-neighbourhood.csv") {
+		t.Fatal("Actual results differ from expected results")
+	}
+}
+
+func TestPerformBfsFromConfigJSONLines(t *testing.T) {
+
+	// Perform BFS writing the results as JSON Lines
+	if err := PerformBfsFromConfig(context.Background(), "./test/test-data-full/config-jsonl.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the result
+	if !FilesHaveSameContent("./test/test-data-full/expected_results.jsonl", "./test/test-data-full/results.jsonl") {
+		t.Fatal("Actual results differ from expected results")
+	}
+}
+
+func TestPerformBfsFromConfigJSON(t *testing.T) {
+
+	// Perform BFS writing the results as a JSON document
+	if err := PerformBfsFromConfig(context.Background(), "./test/test-data-full/config-json.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the result
+	if !FilesHaveSameContent("./test/test-data-full/expected_results.json", "./test/test-data-full/results.json") {
+		t.Fatal("Actual results differ from expected results")
+	}
+
+	// The file can be decoded
+	contents, err := ioutil.ReadFile("./test/test-data-full/results.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := struct {
+		Results []PathResult `json:"results"`
+		Summary Summary      `json:"summary"`
+	}{}
+
+	if err := json.Unmarshal(contents, &decoded); err != nil {
+		t.Fatalf("Unable to decode JSON results: %v\n", err)
+	}
+
+	expected := Summary{
+		TotalPairs:     45,
+		PairsProcessed: 45,
+		PairsWithPaths: 14,
+		PathsFound:     14,
+	}
+
+	if len(decoded.Results) != 14 || decoded.Summary != expected {
+		t.Fatalf("Expected 14 results and summary %v, got %v results and summary %v\n",
+			expected, len(decoded.Results), decoded.Summary)
+	}
+
+	if !reflect.DeepEqual(decoded.Results[0].Path, []string{"e-3", "e-8", "e-11"}) {
+		t.Fatalf("Unexpected path: %v\n", decoded.Results[0].Path)
+	}
+}
+
+func TestPerformBfsFromConfigWithSubgraph(t *testing.T) {
+
+	// Perform BFS and write the subgraph of the paths found
+	if err := PerformBfsFromConfig(context.Background(), "./test/test-data-full/config-subgraph.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the result
+	if !FilesHaveSameContent("./test/test-data-full/expected_results.csv", "./test/test-data-full/results-subgraph.csv") {
+		t.Fatal("Actual results differ from expected results")
+	}
+
+	if !FilesHaveSameContent("./test/test-data-full/expected_results-subgraph.dot", "./test/test-data-full/results-subgraph.dot") {
+		t.Fatal("Actual subgraph differs from expected subgraph")
+	}
+}
+
+func TestPerformBfsFromConfigWithReport(t *testing.T) {
+
+	// Perform BFS and write the HTML report
+	if err := PerformBfsFromConfig(context.Background(), "./test/test-data-full/config-report.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the result
+	if !FilesHaveSameContent("./test/test-data-full/expected_results.csv", "./test/test-data-full/results-report.csv") {
+		t.Fatal("Actual results differ from expected results")
+	}
+
+	report, err := ioutil.ReadFile("./test/test-data-full/results-report.html")
+	if err != nil {
+		t.Fatalf("Unable to read the report: %v\n", err)
+	}
+
+	for _, expected := range []string{
+		"<td class=\"number\">45</td>",
+		"<td>set-1</td><td>set-2</td><td class=\"number\">14</td>",
+		"<a href=\"http://192.168.99.100:8080/show/e-8,e-11\">e-8|e-11</a>",
+		"<tr><th>HTML report</th><td>true</td></tr>",
+	} {
+		if !strings.Contains(string(report), expected) {
+			t.Errorf("Report doesn't contain %v\n", expected)
+		}
+	}
+}
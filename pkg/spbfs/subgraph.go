@@ -0,0 +1,353 @@
+package spbfs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang-collections/collections/set"
+)
+
+// Formats for the subgraph of the paths found
+const (
+	SubgraphFormatGraphML = "graphml" // GraphML (e.g. for yEd)
+	SubgraphFormatGEXF    = "gexf"    // GEXF (e.g. for Gephi)
+	SubgraphFormatDOT     = "dot"     // Graphviz DOT
+)
+
+// Roles of a vertex on a path
+const (
+	RoleSource       = "source"       // first vertex on a path
+	RoleDestination  = "destination"  // last vertex on a path
+	RoleIntermediary = "intermediary" // any other vertex on a path
+)
+
+// subgraphFormat returns the format of the subgraph file, using the file extension if the
+// format isn't set
+func (c *OutputConfig) subgraphFormat() string {
+
+	if len(c.SubgraphFormat) > 0 {
+		return c.SubgraphFormat
+	}
+
+	switch strings.ToLower(filepath.Ext(c.SubgraphFile)) {
+	case ".gexf":
+		return SubgraphFormatGEXF
+	case ".dot", ".gv":
+		return SubgraphFormatDOT
+	}
+
+	return SubgraphFormatGraphML
+}
+
+// Subgraph holds the union of the vertices and edges on the paths found
+type Subgraph struct {
+	Graph       *Graph              // vertices and edges on the paths, with their documents and weights
+	Roles       map[string]*set.Set // roles of each vertex on the paths
+	DataSources map[string]*set.Set // data sources of each source or destination vertex
+}
+
+// NewSubgraph returns an empty subgraph
+func NewSubgraph() *Subgraph {
+	g := NewGraph()
+	return &Subgraph{
+		Graph:       &g,
+		Roles:       make(map[string]*set.Set),
+		DataSources: make(map[string]*set.Set),
+	}
+}
+
+// addLabel adds a label to the set of labels for a vertex
+func addLabel(labels map[string]*set.Set, vertex string, label string) {
+
+	if _, ok := labels[vertex]; !ok {
+		labels[vertex] = set.New()
+	}
+
+	labels[vertex].Insert(label)
+}
+
+// Add adds the vertices and edges on the path of a result, taking the documents and weights of
+// the edges from the graph that was searched. A hop via a virtual document vertex is added as a
+// single edge.
+func (s *Subgraph) Add(g *CompactGraph, result PathResult) error {
+
+	last := len(result.Path) - 1
+
+	for i, vertex := range result.Path {
+
+		switch i {
+		case 0:
+			addLabel(s.Roles, vertex, RoleSource)
+			addLabel(s.DataSources, vertex, result.SourceEntityDataSource)
+		case last:
+			addLabel(s.Roles, vertex, RoleDestination)
+			addLabel(s.DataSources, vertex, result.DestinationEntityDataSource)
+		default:
+			addLabel(s.Roles, vertex, RoleIntermediary)
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		// Add the edge from the previous vertex
+		previous := result.Path[i-1]
+		if err := s.Graph.AddUndirected(previous, vertex); err != nil {
+			return err
+		}
+
+		for _, documentID := range g.hopDocuments(previous, vertex) {
+			if err := s.Graph.AddDocument(previous, vertex, documentID); err != nil {
+				return err
+			}
+		}
+
+		if cost := g.hopCost(previous, vertex); cost != 1.0 {
+			if err := s.Graph.SetWeight(previous, vertex, cost); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// vertexLabel returns the sorted labels of a vertex, separated by the document delimiter
+func vertexLabel(labels map[string]*set.Set, vertex string) string {
+
+	l, ok := labels[vertex]
+	if !ok {
+		return ""
+	}
+
+	return strings.Join(ConvertSetToSlice(l), documentDelimiter)
+}
+
+// vertexDataSource returns the data sources of a vertex, or intermediary if it's only an
+// intermediary on the paths
+func (s *Subgraph) vertexDataSource(vertex string) string {
+
+	if _, ok := s.DataSources[vertex]; ok {
+		return vertexLabel(s.DataSources, vertex)
+	}
+
+	return RoleIntermediary
+}
+
+// subgraphEdge represents an undirected edge of the subgraph
+type subgraphEdge struct {
+	source    string
+	target    string
+	documents string
+	weight    float64
+}
+
+// vertices returns the sorted vertices of the subgraph
+func (s *Subgraph) vertices() []string {
+
+	vertices := make([]string, 0, len(s.Roles))
+	for vertex := range s.Roles {
+		vertices = append(vertices, vertex)
+	}
+	sort.Strings(vertices)
+
+	return vertices
+}
+
+// edges returns the sorted undirected edges of the subgraph
+func (s *Subgraph) edges() []subgraphEdge {
+
+	edges := []subgraphEdge{}
+
+	for _, source := range s.vertices() {
+		for _, target := range s.Graph.AdjacentTo(source) {
+
+			// Each undirected edge is only required once
+			if target < source {
+				continue
+			}
+
+			edges = append(edges, subgraphEdge{
+				source:    source,
+				target:    target,
+				documents: strings.Join(s.Graph.EdgeDocuments(source, target), documentDelimiter),
+				weight:    s.Graph.Weight(source, target),
+			})
+		}
+	}
+
+	return edges
+}
+
+// formatWeight formats the weight of an edge
+func formatWeight(weight float64) string {
+	return strconv.FormatFloat(weight, 'g', -1, 64)
+}
+
+// xmlEscape escapes text for use in an XML attribute or element
+func xmlEscape(text string) string {
+	var buffer bytes.Buffer
+	xml.EscapeText(&buffer, []byte(text))
+	return buffer.String()
+}
+
+// dotQuote quotes text for use as a Graphviz DOT identifier
+func dotQuote(text string) string {
+	return "\"" + strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n").Replace(text) + "\""
+}
+
+// WriteGraphML writes the subgraph in GraphML format
+func (s *Subgraph) WriteGraphML(w io.Writer) error {
+
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintln(b, `<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintln(b, `<graphml xmlns="http://graphml.graphdrawing.org/xmlns">`)
+	fmt.Fprintln(b, `  <key id="label" for="node" attr.name="label" attr.type="string"/>`)
+	fmt.Fprintln(b, `  <key id="role" for="node" attr.name="role" attr.type="string"/>`)
+	fmt.Fprintln(b, `  <key id="data_source" for="node" attr.name="data_source" attr.type="string"/>`)
+	fmt.Fprintln(b, `  <key id="documents" for="edge" attr.name="documents" attr.type="string"/>`)
+	fmt.Fprintln(b, `  <key id="weight" for="edge" attr.name="weight" attr.type="double"/>`)
+	fmt.Fprintln(b, `  <graph id="paths" edgedefault="undirected">`)
+
+	for _, vertex := range s.vertices() {
+		fmt.Fprintf(b, "    <node id=\"%v\">", xmlEscape(vertex))
+		fmt.Fprintf(b, "<data key=\"label\">%v</data>", xmlEscape(vertex))
+		fmt.Fprintf(b, "<data key=\"role\">%v</data>", xmlEscape(vertexLabel(s.Roles, vertex)))
+		fmt.Fprintf(b, "<data key=\"data_source\">%v</data>", xmlEscape(s.vertexDataSource(vertex)))
+		fmt.Fprintln(b, "</node>")
+	}
+
+	for _, edge := range s.edges() {
+		fmt.Fprintf(b, "    <edge source=\"%v\" target=\"%v\">", xmlEscape(edge.source), xmlEscape(edge.target))
+		fmt.Fprintf(b, "<data key=\"documents\">%v</data>", xmlEscape(edge.documents))
+		fmt.Fprintf(b, "<data key=\"weight\">%v</data>", formatWeight(edge.weight))
+		fmt.Fprintln(b, "</edge>")
+	}
+
+	fmt.Fprintln(b, `  </graph>`)
+	fmt.Fprintln(b, `</graphml>`)
+
+	return b.Flush()
+}
+
+// WriteGEXF writes the subgraph in GEXF format
+func (s *Subgraph) WriteGEXF(w io.Writer) error {
+
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintln(b, `<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintln(b, `<gexf xmlns="http://gexf.net/1.3" version="1.3">`)
+	fmt.Fprintln(b, `  <graph mode="static" defaultedgetype="undirected">`)
+	fmt.Fprintln(b, `    <attributes class="node">`)
+	fmt.Fprintln(b, `      <attribute id="role" title="role" type="string"/>`)
+	fmt.Fprintln(b, `      <attribute id="data_source" title="data_source" type="string"/>`)
+	fmt.Fprintln(b, `    </attributes>`)
+	fmt.Fprintln(b, `    <attributes class="edge">`)
+	fmt.Fprintln(b, `      <attribute id="documents" title="documents" type="string"/>`)
+	fmt.Fprintln(b, `    </attributes>`)
+
+	fmt.Fprintln(b, `    <nodes>`)
+	for _, vertex := range s.vertices() {
+		fmt.Fprintf(b, "      <node id=\"%v\" label=\"%v\"><attvalues>", xmlEscape(vertex), xmlEscape(vertex))
+		fmt.Fprintf(b, "<attvalue for=\"role\" value=\"%v\"/>", xmlEscape(vertexLabel(s.Roles, vertex)))
+		fmt.Fprintf(b, "<attvalue for=\"data_source\" value=\"%v\"/>", xmlEscape(s.vertexDataSource(vertex)))
+		fmt.Fprintln(b, "</attvalues></node>")
+	}
+	fmt.Fprintln(b, `    </nodes>`)
+
+	fmt.Fprintln(b, `    <edges>`)
+	for i, edge := range s.edges() {
+		fmt.Fprintf(b, "      <edge id=\"%v\" source=\"%v\" target=\"%v\" weight=\"%v\"><attvalues>",
+			i, xmlEscape(edge.source), xmlEscape(edge.target), formatWeight(edge.weight))
+		fmt.Fprintf(b, "<attvalue for=\"documents\" value=\"%v\"/>", xmlEscape(edge.documents))
+		fmt.Fprintln(b, "</attvalues></edge>")
+	}
+	fmt.Fprintln(b, `    </edges>`)
+
+	fmt.Fprintln(b, `  </graph>`)
+	fmt.Fprintln(b, `</gexf>`)
+
+	return b.Flush()
+}
+
+// WriteDOT writes the subgraph in Graphviz DOT format
+func (s *Subgraph) WriteDOT(w io.Writer) error {
+
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintln(b, "graph paths {")
+
+	for _, vertex := range s.vertices() {
+		fmt.Fprintf(b, "  %v [label=%v, role=%v, data_source=%v];\n",
+			dotQuote(vertex), dotQuote(vertex),
+			dotQuote(vertexLabel(s.Roles, vertex)), dotQuote(s.vertexDataSource(vertex)))
+	}
+
+	for _, edge := range s.edges() {
+		fmt.Fprintf(b, "  %v -- %v [documents=%v, weight=%v];\n",
+			dotQuote(edge.source), dotQuote(edge.target), dotQuote(edge.documents), formatWeight(edge.weight))
+	}
+
+	fmt.Fprintln(b, "}")
+
+	return b.Flush()
+}
+
+// WriteFile writes the subgraph to a file in the required format
+func (s *Subgraph) WriteFile(filePath string, format string) error {
+
+	// Precondition
+	if format != SubgraphFormatGraphML && format != SubgraphFormatGEXF && format != SubgraphFormatDOT {
+		return fmt.Errorf("%w: subgraph format %v", ErrInvalidArgument, format)
+	}
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("unable to open subgraph file %v for writing: %w", filePath, err)
+	}
+	defer file.Close()
+
+	switch format {
+	case SubgraphFormatGraphML:
+		err = s.WriteGraphML(file)
+	case SubgraphFormatGEXF:
+		err = s.WriteGEXF(file)
+	case SubgraphFormatDOT:
+		err = s.WriteDOT(file)
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to write subgraph file %v: %w", filePath, err)
+	}
+
+	return nil
+}
+
+// subgraphWriter adds each path result to the subgraph before passing it to the next writer
+type subgraphWriter struct {
+	resultWriter
+	graph    *CompactGraph
+	subgraph *Subgraph
+}
+
+func (s *subgraphWriter) write(result PathResult) error {
+
+	if result.timedOut() {
+		return s.resultWriter.write(result)
+	}
+
+	if err := s.subgraph.Add(s.graph, result); err != nil {
+		return err
+	}
+
+	return s.resultWriter.write(result)
+}
@@ -0,0 +1,185 @@
+package spbfs
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+)
+
+// testSubgraph builds a subgraph from two paths in a small graph with documents
+func testSubgraph(t *testing.T) *Subgraph {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddDocument("a", "b", "d-1")
+	g.AddDocument("a", "b", "d-2")
+	g.AddUndirected("b", "c")
+	g.AddDocument("b", "c", "d-3")
+	g.SetWeight("b", "c", 0.5)
+	g.AddUndirected("c", "d")
+	c := g.Freeze()
+
+	s := NewSubgraph()
+	if err := s.Add(c, testPathResult(t, "a", "set-1", "c", "set-2", []string{"a", "b", "c"})); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add(c, testPathResult(t, "b", "set-1", "c", "set-2", []string{"b", "c"})); err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func TestSubgraphRoles(t *testing.T) {
+	s := testSubgraph(t)
+
+	testCases := []struct {
+		vertex     string
+		role       string
+		dataSource string
+	}{
+		{"a", "source", "set-1"},
+		{"b", "intermediary;source", "set-1"},
+		{"c", "destination", "set-2"},
+	}
+
+	for _, testCase := range testCases {
+		if role := vertexLabel(s.Roles, testCase.vertex); role != testCase.role {
+			t.Errorf("%v: expected role %v, got %v\n", testCase.vertex, testCase.role, role)
+		}
+
+		if dataSource := s.vertexDataSource(testCase.vertex); dataSource != testCase.dataSource {
+			t.Errorf("%v: expected data source %v, got %v\n", testCase.vertex, testCase.dataSource, dataSource)
+		}
+	}
+
+	// The edge c -- d isn't on a path
+	if len(s.edges()) != 2 {
+		t.Fatalf("Expected 2 edges, got %v\n", len(s.edges()))
+	}
+}
+
+func TestSubgraphIntermediaryOnly(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("a", "b")
+	g.AddUndirected("b", "c")
+
+	s := NewSubgraph()
+	if err := s.Add(g.Freeze(), testPathResult(t, "a", "set-1", "c", "set-2", []string{"a", "b", "c"})); err != nil {
+		t.Fatal(err)
+	}
+
+	if dataSource := s.vertexDataSource("b"); dataSource != RoleIntermediary {
+		t.Fatalf("Expected data source intermediary, got %v\n", dataSource)
+	}
+}
+
+func TestSubgraphWriteDOT(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := testSubgraph(t).WriteDOT(&buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `graph paths {
+  "a" [label="a", role="source", data_source="set-1"];
+  "b" [label="b", role="intermediary;source", data_source="set-1"];
+  "c" [label="c", role="destination", data_source="set-2"];
+  "a" -- "b" [documents="d-1;d-2", weight=1];
+  "b" -- "c" [documents="d-3", weight=0.5];
+}
+`
+
+	if buffer.String() != expected {
+		t.Fatalf("Expected:\n%v\ngot:\n%v\n", expected, buffer.String())
+	}
+}
+
+func TestDotQuote(t *testing.T) {
+	actual := dotQuote(`e "1" \ 2`)
+	expected := `"e \"1\" \\ 2"`
+
+	if actual != expected {
+		t.Fatalf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+// countXMLElements parses an XML document and counts the elements with each name
+func countXMLElements(t *testing.T, document []byte) map[string]int {
+
+	counts := make(map[string]int)
+	decoder := xml.NewDecoder(bytes.NewReader(document))
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("Invalid XML: %v\n", err)
+		}
+
+		if start, ok := token.(xml.StartElement); ok {
+			counts[start.Name.Local]++
+		}
+	}
+
+	return counts
+}
+
+func TestSubgraphWriteGraphML(t *testing.T) {
+	s := testSubgraph(t)
+
+	// Entity IDs that need escaping
+	g := NewGraph()
+	g.AddUndirected("x<1>", "y&2")
+	if err := s.Add(g.Freeze(), testPathResult(t, "x<1>", "set-1", "y&2", "set-2", []string{"x<1>", "y&2"})); err != nil {
+		t.Fatal(err)
+	}
+
+	var buffer bytes.Buffer
+	if err := s.WriteGraphML(&buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	counts := countXMLElements(t, buffer.Bytes())
+
+	if counts["node"] != 5 || counts["edge"] != 3 {
+		t.Fatalf("Expected 5 nodes and 3 edges, got %v and %v\n", counts["node"], counts["edge"])
+	}
+}
+
+func TestSubgraphWriteGEXF(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := testSubgraph(t).WriteGEXF(&buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	counts := countXMLElements(t, buffer.Bytes())
+
+	if counts["node"] != 3 || counts["edge"] != 2 {
+		t.Fatalf("Expected 3 nodes and 2 edges, got %v and %v\n", counts["node"], counts["edge"])
+	}
+}
+
+func TestSubgraphFormat(t *testing.T) {
+	testCases := []struct {
+		file     string
+		format   string
+		expected string
+	}{
+		{"paths.graphml", "", SubgraphFormatGraphML},
+		{"paths.gexf", "", SubgraphFormatGEXF},
+		{"paths.DOT", "", SubgraphFormatDOT},
+		{"paths.gv", "", SubgraphFormatDOT},
+		{"paths.xml", "", SubgraphFormatGraphML},
+		{"paths.xml", SubgraphFormatGEXF, SubgraphFormatGEXF},
+	}
+
+	for _, testCase := range testCases {
+		c := OutputConfig{SubgraphFile: testCase.file, SubgraphFormat: testCase.format}
+		if actual := c.subgraphFormat(); actual != testCase.expected {
+			t.Errorf("%v: expected %v, got %v\n", testCase.file, testCase.expected, actual)
+		}
+	}
+}
@@ -1,7 +1,7 @@
-package main
+package spbfs
 
 import (
-	"log"
+	"fmt"
 )
 
 // TreeNode represents a node in a tree data structure
@@ -25,16 +25,16 @@ func makeTreeNode(name string, marked bool) *TreeNode {
 }
 
 // makeChild makes a child node in the tree
-func (t *TreeNode) makeChild(name string, marked bool) *TreeNode {
+func (t *TreeNode) makeChild(name string, marked bool) (*TreeNode, error) {
 
 	// Check the name is valid
 	if len(name) == 0 {
-		log.Fatal("Child name is empty")
+		return nil, fmt.Errorf("%w: child of %v", ErrEmptyVertex, t.name)
 	}
 
 	// Ensure the vertex is not in the lineage
 	if t.containsVertex(name) {
-		log.Fatalf("Lineage already contains %v", name)
+		return nil, fmt.Errorf("%w: %v", ErrRepeatedVertex, name)
 	}
 
 	// Make the new node
@@ -45,7 +45,7 @@ func (t *TreeNode) makeChild(name string, marked bool) *TreeNode {
 	t.children = append(t.children, node)
 
 	// Return the newly created child node
-	return node
+	return node, nil
 }
 
 // containsVertex determines if a vertex or any of its parents contain a vertex
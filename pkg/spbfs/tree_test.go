@@ -1,6 +1,7 @@
-package main
+package spbfs
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 )
@@ -22,7 +23,10 @@ func TestMakeTreeNode(t *testing.T) {
 
 func TestMakeChild1(t *testing.T) {
 	a := makeTreeNode("a", false)
-	b := a.makeChild("b", true)
+	b, err := a.makeChild("b", true)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if b.parent != a {
 		t.Errorf("Expected 'a' to be parent, got %v", b.parent)
@@ -44,8 +48,15 @@ func TestMakeChild1(t *testing.T) {
 
 func TestMakeChild2(t *testing.T) {
 	a := makeTreeNode("a", false)
-	b := a.makeChild("b", true)
-	c := a.makeChild("c", false)
+	b, err := a.makeChild("b", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := a.makeChild("c", false)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if b.parent != a {
 		t.Errorf("Expected 'a' to be parent, got %v", b.parent)
@@ -87,7 +98,10 @@ func TestContainsVertex1(t *testing.T) {
 
 func TestContainsVertex2(t *testing.T) {
 	a := makeTreeNode("a", false)
-	b := a.makeChild("b", true)
+	b, err := a.makeChild("b", true)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if !a.containsVertex("a") {
 		t.Error("Expected 'a' to contain 'a'")
@@ -119,7 +133,11 @@ func TestFlatten1(t *testing.T) {
 
 func TestFlatten2(t *testing.T) {
 	a := makeTreeNode("a", false)
-	b := a.makeChild("b", false)
+	b, err := a.makeChild("b", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	a.makeChild("c", false)
 
 	actualA := a.flatten()
@@ -143,22 +161,50 @@ func TestComplexCase(t *testing.T) {
 	a := makeTreeNode("a", false)
 
 	// Tier 1
-	b := a.makeChild("b", false)
+	b, err := a.makeChild("b", false)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	// Tier 2
-	c := b.makeChild("c", false)
-	d := b.makeChild("d", false)
+	c, err := b.makeChild("c", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := b.makeChild("d", false)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	// Tier 3
 	c.makeChild("e", false)
-	f := c.makeChild("f", false)
+	f, err := c.makeChild("f", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	d.makeChild("g", false)
-	h := d.makeChild("h", false)
-	j1 := d.makeChild("j", true)
+	h, err := d.makeChild("h", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	j1, err := d.makeChild("j", true)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	// Tier 4
-	j2 := f.makeChild("j", true)
-	j3 := h.makeChild("j", true)
+	j2, err := f.makeChild("j", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	j3, err := h.makeChild("j", true)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	actualPathJ1 := j1.flatten()
 	expectedPathJ1 := []string{"a", "b", "d", "j"}
@@ -181,3 +227,19 @@ func TestComplexCase(t *testing.T) {
 		t.Errorf("Expected %v, got %v", expectedPathJ3, actualPathJ3)
 	}
 }
+
+func TestMakeChildInvalid(t *testing.T) {
+	a := makeTreeNode("a", false)
+	b, err := a.makeChild("b", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := b.makeChild("", false); !errors.Is(err, ErrEmptyVertex) {
+		t.Errorf("Expected %v, got %v\n", ErrEmptyVertex, err)
+	}
+
+	if _, err := b.makeChild("a", false); !errors.Is(err, ErrRepeatedVertex) {
+		t.Errorf("Expected %v, got %v\n", ErrRepeatedVertex, err)
+	}
+}
@@ -0,0 +1,137 @@
+package spbfs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang-collections/collections/set"
+)
+
+// Schemes for deriving the cost of an edge in the unipartite graph from the bipartite graph
+const (
+	WeightUnit    = "unit"    // every edge costs 1
+	WeightCount   = "count"   // 1 / number of documents supporting the edge, so more documents means closer
+	WeightJaccard = "jaccard" // Jaccard distance between the documents of the two entities
+	WeightFile    = "file"    // 1 / sum of the weights of the files of the supporting documents
+)
+
+// validWeightScheme returns true if the edge weight scheme is recognised
+func validWeightScheme(scheme string) bool {
+	return scheme == WeightUnit || scheme == WeightCount || scheme == WeightJaccard || scheme == WeightFile
+}
+
+// entityDocuments returns a map of each entity ID to its set of document IDs
+func entityDocuments(connections *[]EntityDocument) map[string]*set.Set {
+
+	docs := make(map[string]*set.Set)
+
+	for _, conn := range *connections {
+
+		_, present := docs[conn.EntityID]
+		if !present {
+			docs[conn.EntityID] = set.New()
+		}
+
+		docs[conn.EntityID].Insert(conn.DocumentID)
+	}
+
+	return docs
+}
+
+// documentsOfVertex returns the set of document IDs for a vertex, handling virtual document vertices
+func documentsOfVertex(vertex string, docs map[string]*set.Set) *set.Set {
+
+	if d, ok := docs[vertex]; ok {
+		return d
+	}
+
+	// A virtual document vertex is only connected to its own document
+	if isDocumentVertex(vertex) {
+		return set.New(strings.TrimPrefix(vertex, documentVertexPrefix))
+	}
+
+	return set.New()
+}
+
+// jaccardDistance returns 1 - |A ∩ B| / |A ∪ B|
+func jaccardDistance(a *set.Set, b *set.Set) float64 {
+
+	union := a.Union(b).Len()
+	if union == 0 {
+		return 1.0
+	}
+
+	return 1.0 - float64(a.Intersection(b).Len())/float64(union)
+}
+
+// ComputeEdgeWeights sets the cost of each edge in the graph using the required scheme. The
+// document weights map a document ID to the weight of the file it was read from (default 1).
+func ComputeEdgeWeights(g *Graph, connections *[]EntityDocument, scheme string, documentWeights map[string]float64) error {
+
+	// Precondition
+	if !validWeightScheme(scheme) {
+		return fmt.Errorf("%w: edge weight scheme %v", ErrInvalidArgument, scheme)
+	}
+
+	// Unit weights don't need to be stored
+	if scheme == WeightUnit {
+		return nil
+	}
+
+	// Documents for each entity (only required for the Jaccard distance)
+	var docs map[string]*set.Set
+	if scheme == WeightJaccard {
+		docs = entityDocuments(connections)
+	}
+
+	// Walk through each edge
+	var err error
+
+	for source, destinations := range g.Nodes {
+		destinations.Do(func(s interface{}) {
+
+			destination := s.(string)
+
+			// Each undirected edge only needs to be processed once (and stop at the first error).
+			// A directed edge doesn't have a reverse edge, so it's always processed.
+			if err != nil || (destination < source && g.Nodes[destination] != nil && g.Nodes[destination].Has(source)) {
+				return
+			}
+
+			// Edges without supporting documents keep the default cost
+			supporting := g.EdgeDocuments(source, destination)
+			if len(supporting) == 0 {
+				return
+			}
+
+			count := float64(len(supporting))
+
+			var weight float64
+
+			switch scheme {
+			case WeightCount:
+				weight = 1.0 / count
+			case WeightJaccard:
+				weight = jaccardDistance(documentsOfVertex(source, docs), documentsOfVertex(destination, docs))
+			case WeightFile:
+				total := 0.0
+				for _, documentID := range supporting {
+					if w, ok := documentWeights[documentID]; ok {
+						total += w
+					} else {
+						total += 1.0
+					}
+				}
+				weight = 1.0 / total
+			}
+
+			err = g.SetWeight(source, destination, weight)
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
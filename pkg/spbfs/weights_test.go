@@ -0,0 +1,119 @@
+package spbfs
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// weightsTestConnections returns connections where e-1 and e-2 share two of their three documents
+func weightsTestConnections() []EntityDocument {
+	return []EntityDocument{
+		{EntityID: "e-1", DocumentID: "d-1"},
+		{EntityID: "e-2", DocumentID: "d-1"},
+		{EntityID: "e-1", DocumentID: "d-2"},
+		{EntityID: "e-2", DocumentID: "d-2"},
+		{EntityID: "e-1", DocumentID: "d-3"},
+		{EntityID: "e-2", DocumentID: "d-4"},
+		{EntityID: "e-3", DocumentID: "d-4"},
+	}
+}
+
+func TestComputeEdgeWeightsUnit(t *testing.T) {
+	connections := weightsTestConnections()
+	g, err := BipartiteToUnipartite(&connections, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ComputeEdgeWeights(g, &connections, WeightUnit, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if g.Weight("e-1", "e-2") != 1 || g.Weight("e-2", "e-3") != 1 {
+		t.Errorf("Expected unit weights")
+	}
+}
+
+func TestComputeEdgeWeightsCount(t *testing.T) {
+	connections := weightsTestConnections()
+	g, err := BipartiteToUnipartite(&connections, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ComputeEdgeWeights(g, &connections, WeightCount, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The edge supported by more documents is cheaper
+	if g.Weight("e-1", "e-2") != 0.5 {
+		t.Errorf("Expected a weight of 0.5, got %v\n", g.Weight("e-1", "e-2"))
+	}
+
+	if g.Weight("e-3", "e-2") != 1 {
+		t.Errorf("Expected a weight of 1, got %v\n", g.Weight("e-3", "e-2"))
+	}
+}
+
+func TestComputeEdgeWeightsJaccard(t *testing.T) {
+	connections := weightsTestConnections()
+	g, err := BipartiteToUnipartite(&connections, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ComputeEdgeWeights(g, &connections, WeightJaccard, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// e-1 = {d-1, d-2, d-3} and e-2 = {d-1, d-2, d-4}, so the distance is 1 - 2/4
+	if g.Weight("e-1", "e-2") != 0.5 {
+		t.Errorf("Expected a weight of 0.5, got %v\n", g.Weight("e-1", "e-2"))
+	}
+
+	// e-2 = {d-1, d-2, d-4} and e-3 = {d-4}, so the distance is 1 - 1/3
+	if math.Abs(g.Weight("e-2", "e-3")-2.0/3.0) > 1e-9 {
+		t.Errorf("Expected a weight of 2/3, got %v\n", g.Weight("e-2", "e-3"))
+	}
+}
+
+func TestComputeEdgeWeightsFile(t *testing.T) {
+	connections := weightsTestConnections()
+	g, err := BipartiteToUnipartite(&connections, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	documentWeights := map[string]float64{
+		"d-1": 1.0,
+		"d-2": 3.0,
+		"d-4": 0.5,
+	}
+
+	if err := ComputeEdgeWeights(g, &connections, WeightFile, documentWeights); err != nil {
+		t.Fatal(err)
+	}
+
+	if g.Weight("e-1", "e-2") != 0.25 {
+		t.Errorf("Expected a weight of 0.25, got %v\n", g.Weight("e-1", "e-2"))
+	}
+
+	if g.Weight("e-2", "e-3") != 2 {
+		t.Errorf("Expected a weight of 2, got %v\n", g.Weight("e-2", "e-3"))
+	}
+}
+
+func TestComputeEdgeWeightsInvalidScheme(t *testing.T) {
+	connections := weightsTestConnections()
+	g, err := BipartiteToUnipartite(&connections, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, scheme := range []string{"unknown", "inverse_count"} {
+		if err := ComputeEdgeWeights(g, &connections, scheme, nil); !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("%v: expected %v, got %v\n", scheme, ErrInvalidArgument, err)
+		}
+	}
+}
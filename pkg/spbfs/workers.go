@@ -0,0 +1,423 @@
+package spbfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/golang-collections/collections/set"
+)
+
+// workUnit represents the pairs of entities for a single source entity
+type workUnit struct {
+	index                 int      // position of the unit when processed by a single worker
+	id                    int      // position of the unit amongst all of the units (the same when resuming)
+	source                string   // source entity ID
+	sourceDataSource      string   // data source of the source entity
+	destinations          []string // destination entity IDs
+	destinationDataSource string   // data source of the destination entities
+	neighbourhood         bool     // find every entity within reach of the source (no destinations)
+}
+
+// unitResult represents the paths found for a work unit
+type unitResult struct {
+	index          int          // position of the unit when processed by a single worker
+	unit           workUnit     // unit that was processed
+	results        []PathResult // paths found
+	pairsProcessed int          // number of entity pairs processed
+	pairsWithPaths int          // number of entity pairs connected by a path
+	pairsTimedOut  int          // number of entity pairs whose search ran out of time
+	err            error        // error that stopped the unit from being processed
+}
+
+// buildWorkUnits splits the pairs of entities to check into units, one per source entity and
+// destination data source
+func buildWorkUnits(entityConfig EntityConfig) []workUnit {
+
+	units := []workUnit{}
+	pairMode := entityConfig.pairMode()
+
+	// Each seed entity is a unit in neighbourhood mode
+	if pairMode == PairModeNeighbourhood {
+		for _, dataSource := range entityConfig.DataSources {
+			for _, source := range dataSource.EntityIds {
+				units = append(units, workUnit{
+					index:                 len(units),
+					id:                    len(units),
+					source:                source,
+					sourceDataSource:      dataSource.Name,
+					destinationDataSource: PairModeNeighbourhood,
+					neighbourhood:         true,
+				})
+			}
+		}
+		return units
+	}
+
+	// Walk through all pairs of data sources
+	for i := 0; hasCrossPairs(pairMode) && i < len(entityConfig.DataSources)-1; i++ {
+		for j := i + 1; j < len(entityConfig.DataSources); j++ {
+
+			// Walk through each source entity in the i(th) dataset
+			for _, source := range entityConfig.DataSources[i].EntityIds {
+				units = append(units, workUnit{
+					index:                 len(units),
+					id:                    len(units),
+					source:                source,
+					sourceDataSource:      entityConfig.DataSources[i].Name,
+					destinations:          entityConfig.DataSources[j].EntityIds,
+					destinationDataSource: entityConfig.DataSources[j].Name,
+				})
+			}
+		}
+	}
+
+	// Walk through the pairs of entities within each data source, where each unordered pair is
+	// only searched once
+	for _, dataSource := range entityConfig.DataSources {
+
+		if !dataSource.hasWithinPairs(pairMode) {
+			continue
+		}
+
+		for k := 0; k < len(dataSource.EntityIds)-1; k++ {
+			units = append(units, workUnit{
+				index:                 len(units),
+				id:                    len(units),
+				source:                dataSource.EntityIds[k],
+				sourceDataSource:      dataSource.Name,
+				destinations:          dataSource.EntityIds[k+1:],
+				destinationDataSource: dataSource.Name,
+			})
+		}
+	}
+
+	return units
+}
+
+// buildPairWorkUnits splits the pairs of entities into units. Consecutive pairs with the same
+// source entity and labels share a unit, so the order of the pairs is preserved.
+func buildPairWorkUnits(pairs []EntityPair) []workUnit {
+
+	units := []workUnit{}
+
+	for _, pair := range pairs {
+
+		// Add the destination to the previous unit if it's for the same source
+		if n := len(units); n > 0 &&
+			units[n-1].source == pair.Source &&
+			units[n-1].sourceDataSource == pair.SourceLabel &&
+			units[n-1].destinationDataSource == pair.DestinationLabel {
+
+			units[n-1].destinations = append(units[n-1].destinations, pair.Destination)
+			continue
+		}
+
+		units = append(units, workUnit{
+			index:                 len(units),
+			id:                    len(units),
+			source:                pair.Source,
+			sourceDataSource:      pair.SourceLabel,
+			destinations:          []string{pair.Destination},
+			destinationDataSource: pair.DestinationLabel,
+		})
+	}
+
+	return units
+}
+
+// processWorkUnit finds the paths from the source entity to each of the destination entities
+func processWorkUnit(ctx context.Context, c *CompactGraph, unit workUnit, skipEntities *set.Set, outputConfig OutputConfig) unitResult {
+
+	result := unitResult{
+		index:   unit.index,
+		unit:    unit,
+		results: []PathResult{},
+	}
+
+	// Don't start the unit if the run has been cancelled
+	if err := ctx.Err(); err != nil {
+		result.err = err
+		return result
+	}
+
+	// Find every entity within reach of the seed entity (unless it needs to be skipped)
+	if unit.neighbourhood {
+		result.pairsProcessed = 1
+		if !skipEntities.Has(unit.source) {
+			result.results, result.err = findNeighbourhoodResults(ctx, c, unit, skipEntities, outputConfig)
+		}
+
+		// The seed ran out of time, but the run hasn't been cancelled
+		if errors.Is(result.err, context.DeadlineExceeded) && ctx.Err() == nil {
+			result.results = []PathResult{newTimedOutResult(unit.source, unit.sourceDataSource,
+				"", unit.destinationDataSource)}
+			result.pairsTimedOut = 1
+			result.err = nil
+			return result
+		}
+
+		if len(result.results) > 0 {
+			result.pairsWithPaths = 1
+		}
+		return result
+	}
+
+	// Skip the source entity if required
+	if skipEntities.Has(unit.source) {
+		// Don't need to check all paths to the destinations
+		result.pairsProcessed = len(unit.destinations)
+		return result
+	}
+
+	// Set of all vertices within reach of the source vertex
+	found, reachable, err := reachableFrom(ctx, c, unit.source, outputConfig)
+	if err != nil {
+		result.err = err
+		return result
+	}
+
+	// If the source vertex was not found in the dataset, just continue to the next vertex
+	if !found {
+		result.pairsProcessed = len(unit.destinations)
+		return result
+	}
+
+	if reachable != nil {
+		defer reachable.Release()
+	}
+
+	// Walk through each destination entity
+	for _, destination := range unit.destinations {
+
+		result.pairsProcessed++
+
+		// Skip the entity if it's both source and destination or if it needs to be skipped
+		if (unit.source == destination) || skipEntities.Has(destination) {
+			continue
+		}
+
+		// If the destination is reachable from the source, then find the shortest path
+		if reachable == nil || reachable.Has(destination) {
+			paths, err := searchPair(ctx, c, unit, destination, outputConfig)
+
+			// The pair ran out of time, but the run hasn't been cancelled
+			if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+				result.results = append(result.results, newTimedOutResult(unit.source, unit.sourceDataSource,
+					destination, unit.destinationDataSource))
+				result.pairsTimedOut++
+				continue
+			}
+
+			if err != nil {
+				result.err = err
+				return result
+			}
+
+			if len(paths) > 0 {
+				result.results = append(result.results, paths...)
+				result.pairsWithPaths++
+			}
+		}
+	}
+
+	return result
+}
+
+// searchPair finds the paths from the source entity of a unit to a destination entity, limiting
+// the time taken to the pair timeout (if any)
+func searchPair(ctx context.Context, c *CompactGraph, unit workUnit, destination string, outputConfig OutputConfig) ([]PathResult, error) {
+
+	if timeout := outputConfig.pairTimeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	return findPathResults(ctx, c,
+		unit.source, unit.sourceDataSource,
+		destination, unit.destinationDataSource,
+		outputConfig)
+}
+
+// findNeighbourhoodResults finds the entities within reach of the seed entity of a unit, with a
+// shortest path to each, limiting the time taken to the pair timeout (if any)
+func findNeighbourhoodResults(ctx context.Context, c *CompactGraph, unit workUnit, skipEntities *set.Set, outputConfig OutputConfig) ([]PathResult, error) {
+
+	if timeout := outputConfig.pairTimeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	results := []PathResult{}
+
+	// The entities to skip aren't reported, so they're removed before the number of results is limited
+	include := func(identifier string) bool {
+		return !skipEntities.Has(identifier)
+	}
+
+	found, paths, err := c.Neighbourhood(ctx, unit.source, outputConfig.MinDepth, outputConfig.MaxDepth,
+		outputConfig.MaxResultsPerSeed, include)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return results, nil
+	}
+
+	for _, path := range paths {
+
+		destination := path[len(path)-1]
+		result, err := buildPathResult(c, unit.source, unit.sourceDataSource,
+			destination, unit.destinationDataSource,
+			path, 1, outputConfig)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result.Mode = PairModeNeighbourhood
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// processWorkUnits processes the work units using a pool of workers. The results are returned on the
+// channel in the order they complete, which is closed once all of the units are processed. No more
+// units are started once the context is cancelled.
+func processWorkUnits(ctx context.Context, c *CompactGraph, units []workUnit, skipEntities *set.Set, outputConfig OutputConfig) <-chan unitResult {
+
+	numWorkers := outputConfig.numWorkers()
+
+	jobs := make(chan workUnit)
+	results := make(chan unitResult, numWorkers)
+
+	// Start the workers
+	var wg sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for unit := range jobs {
+				results <- processWorkUnit(ctx, c, unit, skipEntities, outputConfig)
+			}
+		}()
+	}
+
+	// Send the work units to the workers
+	go func() {
+		defer close(jobs)
+
+		previous := ""
+		for _, unit := range units {
+
+			dataSources := unit.sourceDataSource + " <--> " + unit.destinationDataSource
+			if dataSources != previous {
+				log.Printf("Checking connections for data sources %v\n", dataSources)
+				previous = dataSources
+			}
+
+			select {
+			case jobs <- unit:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Close the results channel when the workers have finished
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// writeUnitResult writes the paths found for a work unit to file, records the unit in the
+// checkpoint (if any) and updates the summary
+func writeUnitResult(result unitResult, writer resultWriter, cp *checkpoint, outputConfig OutputConfig, summary *Summary) error {
+
+	if result.err != nil {
+		return result.err
+	}
+
+	for _, pathResult := range result.results {
+
+		// Display the result
+		log.Printf("%v\n", pathResult.display())
+
+		// Add the result to the file
+		if err := writer.write(pathResult); err != nil {
+			return fmt.Errorf("unable to write to output file %v: %w", outputConfig.OutputFile, err)
+		}
+	}
+
+	// Make the results available in the file as soon as the unit is complete
+	if err := writer.flush(); err != nil {
+		return fmt.Errorf("unable to write to output file %v: %w", outputConfig.OutputFile, err)
+	}
+
+	if cp != nil {
+		if err := cp.record(result); err != nil {
+			return err
+		}
+	}
+
+	// Provide feedback on long-running jobs
+	if (summary.PairsProcessed+result.pairsProcessed)/10000 > summary.PairsProcessed/10000 {
+		log.Printf("Processed %v pairs of %v\n", summary.PairsProcessed+result.pairsProcessed, summary.TotalPairs)
+	}
+
+	summary.PairsProcessed += result.pairsProcessed
+	summary.PairsWithPaths += result.pairsWithPaths
+	summary.PathsFound += len(result.results) - result.pairsTimedOut
+	summary.PairsTimedOut += result.pairsTimedOut
+
+	return nil
+}
+
+// writeUnitResults writes the results from the workers to file. If the results are ordered, then
+// they are written in the same order as a single worker would produce them. After an error, the
+// remaining results are discarded so that the workers can finish, and the first error is returned.
+func writeUnitResults(results <-chan unitResult, writer resultWriter, cp *checkpoint, outputConfig OutputConfig, summary *Summary) error {
+
+	// Results waiting for earlier units to complete
+	pending := make(map[int]unitResult)
+	next := 0
+
+	var err error
+
+	for result := range results {
+
+		if err != nil {
+			continue
+		}
+
+		if !outputConfig.Ordered {
+			err = writeUnitResult(result, writer, cp, outputConfig, summary)
+			continue
+		}
+
+		pending[result.index] = result
+
+		// Write the results that are next in order
+		for {
+			r, ok := pending[next]
+			if !ok || err != nil {
+				break
+			}
+
+			err = writeUnitResult(r, writer, cp, outputConfig, summary)
+			delete(pending, next)
+			next++
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,310 @@
+package spbfs
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/golang-collections/collections/set"
+)
+
+func TestBuildWorkUnits(t *testing.T) {
+	entityConfig := EntityConfig{
+		DataSources: []DataSource{
+			{Name: "set-1", EntityIds: []string{"e-1", "e-2"}},
+			{Name: "set-2", EntityIds: []string{"e-3"}},
+			{Name: "set-3", EntityIds: []string{"e-4", "e-5"}},
+		},
+	}
+
+	units := buildWorkUnits(entityConfig)
+
+	expected := []workUnit{
+		{index: 0, id: 0, source: "e-1", sourceDataSource: "set-1", destinations: []string{"e-3"}, destinationDataSource: "set-2"},
+		{index: 1, id: 1, source: "e-2", sourceDataSource: "set-1", destinations: []string{"e-3"}, destinationDataSource: "set-2"},
+		{index: 2, id: 2, source: "e-1", sourceDataSource: "set-1", destinations: []string{"e-4", "e-5"}, destinationDataSource: "set-3"},
+		{index: 3, id: 3, source: "e-2", sourceDataSource: "set-1", destinations: []string{"e-4", "e-5"}, destinationDataSource: "set-3"},
+		{index: 4, id: 4, source: "e-3", sourceDataSource: "set-2", destinations: []string{"e-4", "e-5"}, destinationDataSource: "set-3"},
+	}
+
+	if !reflect.DeepEqual(expected, units) {
+		t.Errorf("Expected %v, got %v\n", expected, units)
+	}
+}
+
+func TestBuildWorkUnitsWithin(t *testing.T) {
+	entityConfig := EntityConfig{
+		DataSources: []DataSource{
+			{Name: "set-1", EntityIds: []string{"e-1", "e-2", "e-3"}},
+			{Name: "set-2", EntityIds: []string{"e-4"}, SelfPairs: true},
+		},
+		PairMode: PairModeAll,
+	}
+
+	units := buildWorkUnits(entityConfig)
+
+	expected := []workUnit{
+		{index: 0, id: 0, source: "e-1", sourceDataSource: "set-1", destinations: []string{"e-4"}, destinationDataSource: "set-2"},
+		{index: 1, id: 1, source: "e-2", sourceDataSource: "set-1", destinations: []string{"e-4"}, destinationDataSource: "set-2"},
+		{index: 2, id: 2, source: "e-3", sourceDataSource: "set-1", destinations: []string{"e-4"}, destinationDataSource: "set-2"},
+		{index: 3, id: 3, source: "e-1", sourceDataSource: "set-1", destinations: []string{"e-2", "e-3"}, destinationDataSource: "set-1"},
+		{index: 4, id: 4, source: "e-2", sourceDataSource: "set-1", destinations: []string{"e-3"}, destinationDataSource: "set-1"},
+	}
+
+	if !reflect.DeepEqual(expected, units) {
+		t.Errorf("Expected %v, got %v\n", expected, units)
+	}
+
+	// Only the pairs within the data sources
+	entityConfig.PairMode = PairModeWithin
+	units = buildWorkUnits(entityConfig)
+
+	if len(units) != 2 || units[0].source != "e-1" || units[0].destinationDataSource != "set-1" {
+		t.Errorf("Unexpected units for pairs within the data sources: %v\n", units)
+	}
+}
+
+func TestBuildPairWorkUnits(t *testing.T) {
+	pairs := []EntityPair{
+		{Source: "e-1", Destination: "e-2", SourceLabel: "a", DestinationLabel: "b"},
+		{Source: "e-1", Destination: "e-3", SourceLabel: "a", DestinationLabel: "b"},
+		{Source: "e-1", Destination: "e-4", SourceLabel: "a", DestinationLabel: "c"},
+		{Source: "e-2", Destination: "e-3", SourceLabel: "a", DestinationLabel: "c"},
+		{Source: "e-1", Destination: "e-5", SourceLabel: "a", DestinationLabel: "c"},
+	}
+
+	units := buildPairWorkUnits(pairs)
+
+	expected := []workUnit{
+		{index: 0, id: 0, source: "e-1", sourceDataSource: "a", destinations: []string{"e-2", "e-3"}, destinationDataSource: "b"},
+		{index: 1, id: 1, source: "e-1", sourceDataSource: "a", destinations: []string{"e-4"}, destinationDataSource: "c"},
+		{index: 2, id: 2, source: "e-2", sourceDataSource: "a", destinations: []string{"e-3"}, destinationDataSource: "c"},
+		{index: 3, id: 3, source: "e-1", sourceDataSource: "a", destinations: []string{"e-5"}, destinationDataSource: "c"},
+	}
+
+	if !reflect.DeepEqual(expected, units) {
+		t.Errorf("Expected %v, got %v\n", expected, units)
+	}
+}
+
+// performBfsWithWorkers runs the analysis of the full test data with multiple workers
+func performBfsWithWorkers(t *testing.T, ordered bool, outputFile string) Summary {
+	config, err := ReadConfig("./test/test-data-full/config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config.Output.Workers = 4
+	config.Output.Ordered = ordered
+	config.Output.OutputFile = outputFile
+
+	connections, _, err := ReadInputFiles(config.InputFiles, SliceToSet(config.Entities.Skip))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := BipartiteToUnipartite(connections, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summary, err := performBfs(context.Background(), g, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return summary
+}
+
+func TestPerformBfsWorkersOrdered(t *testing.T) {
+
+	summary := performBfsWithWorkers(t, true, "./test/test-data-full/results-workers-ordered.csv")
+
+	// The results are in the same order as for a single worker
+	if !FilesHaveSameContent("./test/test-data-full/expected_results.csv", "./test/test-data-full/results-workers-ordered.csv") {
+		t.Fatal("Actual results differ from expected results")
+	}
+
+	expected := Summary{
+		TotalPairs:     45,
+		PairsProcessed: 45,
+		PairsWithPaths: 14,
+		PathsFound:     14,
+	}
+
+	if !reflect.DeepEqual(expected, summary) {
+		t.Errorf("Expected %v, got %v\n", expected, summary)
+	}
+}
+
+func TestPerformBfsWorkersUnordered(t *testing.T) {
+
+	performBfsWithWorkers(t, false, "./test/test-data-full/results-workers-unordered.csv")
+
+	// The results may be in any order
+	if !FilesHaveSameContentIgnoringOrder("./test/test-data-full/expected_results.csv", "./test/test-data-full/results-workers-unordered.csv") {
+		t.Fatal("Actual results differ from expected results")
+	}
+}
+
+func TestProcessWorkUnitPairTimeout(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("e-1", "e-2")
+	g.AddUndirected("e-2", "e-3")
+	c := g.Freeze()
+
+	unit := workUnit{
+		source:                "e-1",
+		sourceDataSource:      "set-1",
+		destinations:          []string{"e-3", "e-4"},
+		destinationDataSource: "set-2",
+	}
+
+	// The deadline for each pair has passed before the search starts
+	outputConfig := OutputConfig{MaxDepth: 3, PathMode: PathModeAllSimple, PairTimeout: "1ns"}
+	result := processWorkUnit(context.Background(), c, unit, set.New(), outputConfig)
+
+	if result.err != nil {
+		t.Fatal(result.err)
+	}
+
+	// e-4 isn't reachable, so it isn't searched
+	expected := []PathResult{newTimedOutResult("e-1", "set-1", "e-3", "set-2")}
+
+	if !reflect.DeepEqual(expected, result.results) {
+		t.Errorf("Expected %v, got %v\n", expected, result.results)
+	}
+
+	if result.pairsProcessed != 2 || result.pairsWithPaths != 0 || result.pairsTimedOut != 1 {
+		t.Errorf("Expected 2 pairs processed and 1 timed out, got %v\n", result)
+	}
+
+	// Without a timeout, the path is found
+	outputConfig.PairTimeout = ""
+	result = processWorkUnit(context.Background(), c, unit, set.New(), outputConfig)
+
+	if result.err != nil || result.pairsWithPaths != 1 || result.pairsTimedOut != 0 {
+		t.Errorf("Expected a path without a timeout, got %v\n", result)
+	}
+}
+
+func TestProcessWorkUnitPairTimeoutAlgorithms(t *testing.T) {
+	g := NewGraph()
+	g.AddUndirected("e-1", "e-2")
+	g.AddUndirected("e-2", "e-3")
+	c := g.Freeze()
+
+	unit := workUnit{
+		source:                "e-1",
+		sourceDataSource:      "set-1",
+		destinations:          []string{"e-3"},
+		destinationDataSource: "set-2",
+	}
+
+	testCases := []struct {
+		description  string
+		outputConfig OutputConfig
+	}{
+		{"dijkstra", OutputConfig{MaxDepth: 3, Algorithm: AlgorithmDijkstra, EdgeWeight: WeightUnit}},
+		{"bidirectional", OutputConfig{MaxDepth: 3, Algorithm: AlgorithmBidirectional}},
+		{"k shortest", OutputConfig{MaxDepth: 3, PathMode: PathModeKShortest, MaxPathsPerPair: 2}},
+	}
+
+	for _, testCase := range testCases {
+
+		// The deadline for each pair has passed before the search starts
+		testCase.outputConfig.PairTimeout = "1ns"
+		result := processWorkUnit(context.Background(), c, unit, set.New(), testCase.outputConfig)
+
+		if result.err != nil || result.pairsTimedOut != 1 || result.pairsWithPaths != 0 {
+			t.Errorf("%v: expected the pair to time out, got %v\n", testCase.description, result)
+		}
+	}
+
+	// A seed entity that runs out of time in neighbourhood mode
+	unit = workUnit{
+		source:                "e-1",
+		sourceDataSource:      "set-1",
+		destinationDataSource: PairModeNeighbourhood,
+		neighbourhood:         true,
+	}
+
+	result := processWorkUnit(context.Background(), c, unit, set.New(), OutputConfig{MaxDepth: 3, PairTimeout: "1ns"})
+	expected := []PathResult{newTimedOutResult("e-1", "set-1", "", PairModeNeighbourhood)}
+
+	if result.err != nil || result.pairsTimedOut != 1 || !reflect.DeepEqual(expected, result.results) {
+		t.Errorf("Expected the seed entity to time out, got %v\n", result)
+	}
+}
+
+func TestFindNeighbourhoodResultsLimit(t *testing.T) {
+	connections := largeDocumentConnections()
+
+	g, err := BipartiteToUnipartite(&connections, 3, LargeDocumentStar)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unit := workUnit{
+		source:                "e-1",
+		sourceDataSource:      "set-1",
+		destinationDataSource: PairModeNeighbourhood,
+		neighbourhood:         true,
+	}
+
+	// The document vertex and the skipped entity don't count towards the maximum number of results
+	outputConfig := OutputConfig{MaxDepth: 3, MaxResultsPerSeed: 2}
+	results, err := findNeighbourhoodResults(context.Background(), g.Freeze(), unit, set.New("e-2"), outputConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	destinations := []string{}
+	for _, result := range results {
+		destinations = append(destinations, result.DestinationEntityID)
+	}
+
+	if expected := []string{"e-3", "e-4"}; !reflect.DeepEqual(expected, destinations) {
+		t.Errorf("Expected %v, got %v\n", expected, destinations)
+	}
+}
+
+func TestPerformBfsPairTimeout(t *testing.T) {
+	config, err := ReadConfig("./test/test-data-full/config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config.Output.OutputFile = filepath.Join(t.TempDir(), "results.csv")
+	config.Output.PairTimeout = "1ns"
+
+	connections, _, err := ReadInputFiles(config.InputFiles, SliceToSet(config.Entities.Skip))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := BipartiteToUnipartite(connections, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summary, err := performBfs(context.Background(), g, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every reachable pair times out
+	if summary.PairsTimedOut != 14 || summary.PathsFound != 0 || summary.PairsProcessed != 45 {
+		t.Errorf("Expected 14 pairs to time out, got %v\n", summary)
+	}
+
+	// The run stops when it's cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := performBfs(ctx, g, config); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected %v, got %v\n", context.Canceled, err)
+	}
+}